@@ -2,23 +2,30 @@ package cellularautomata
 
 import (
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/gopxl/pixel/v2"
 	"github.com/gopxl/pixel/v2/backends/opengl"
+	"github.com/michael-ryan/cellularautomata/v2/editor"
 	"github.com/michael-ryan/cellularautomata/v2/model"
+	"github.com/michael-ryan/cellularautomata/v2/rle"
+	"github.com/michael-ryan/cellularautomata/v2/simulation"
 )
 
 type Config struct {
 	// Fps defines the target FPS of the simulation. This is the number of simulated time steps, per second.
 	//
 	// If Fps is sufficiently high, the simulation will simply run as fast as the hardware allows.
+	// While the simulation runs, pressing + and - doubles and halves the speed.
 	Fps uint
 	// CellsX and CellsY define the dimensions of the grid of cells.
 	CellsX, CellsY uint
 	// WindowX and WindowY define the number of pixels the GUI canvas should span.
 	//
 	// These values should equal or exceed CellsX and CellsY respectively.
+	// To inspect a large grid cell by cell, or scale a small one up further, the mouse wheel zooms in and out around the pointer,
+	// dragging with the middle mouse button pans, and pressing Home shows the whole grid again.
 	WindowX, WindowY uint
 	// Automaton defines the cell states, their colours and their transition rules.
 	//
@@ -26,9 +33,63 @@ type Config struct {
 	Automaton *model.Automaton
 	// InitialState defines the initial state of all cells on the grid.
 	InitialState uint
-	// SkipEditor denotes whether to skip the initial edit mode of the grid. If false, the program will launch in edit mode, and the user can click on cells to cycle their initial state.
+	// InitialGrid optionally defines the initial state of each cell individually, indexed as InitialGrid[x][y]. If set, it must be CellsX by CellsY, and it takes precedence over InitialState.
+	//
+	// A grid can be loaded from an RLE file with [rle.ReadFile] and [rle.Pattern.Place].
+	InitialGrid [][]uint
+	// SkipEditor denotes whether to skip the initial edit mode of the grid. If false, the program will launch in edit mode, and the user can paint the initial state of cells.
 	// Pressing S on the keyboard will start the simulation.
+	//
+	// In edit mode, dragging with the left mouse button paints with the chosen state, and dragging with the right mouse button erases to InitialState.
+	// States are chosen with the number keys, or by clicking on the palette across the top of the window, which P shows and hides.
+	// Tab cycles between the brush, line, rectangle and flood fill tools, and [ and ] shrink and grow the brush. The window title shows what is being drawn with.
+	// Ctrl+Z undoes the last stroke, and Ctrl+Y redoes it. Returning to edit mode records the run as a single action, so it can be undone to get back to where the simulation started.
+	//
+	// While the simulation runs, pressing space pauses and resumes it, N advances a paused simulation by a single generation,
+	// R resets it to the grid it started from, and E returns to edit mode with the current grid.
 	SkipEditor bool
+	// GridFile is the path of an RLE file used to save and reload the grid in edit mode.
+	// Pressing W on the keyboard writes the grid to GridFile, and pressing L replaces the grid with the pattern in GridFile, centred on a background of InitialState.
+	// If empty, these shortcuts are disabled.
+	GridFile string
+	// Spacetime denotes whether to render a one-dimensional automaton, such as one made with [model.NewElementaryAutomaton], as a spacetime diagram.
+	// If true, the simulation runs on a single row of CellsX cells, and each generation is drawn as a row of the window, starting at the top.
+	// Once the window is full, older generations scroll off the top. CellsY is the number of generations visible at once.
+	//
+	// In edit mode, painting anywhere in a column paints the cell at the bottom of it. InitialGrid, if set, must be CellsX by 1.
+	Spacetime bool
+	// Automaton3D, if set, simulates a three-dimensional automaton in place of Automaton, on a grid of CellsX by CellsY by CellsZ cells.
+	// The window shows a single plane of the grid, z = SliceZ, through the same canvas used for two-dimensional automata.
+	// Pressing the up and down arrow keys moves between planes.
+	//
+	// Edit mode and GridFile work on the plane being shown. Spacetime and Observers are not supported for three-dimensional automata.
+	Automaton3D *model.Automaton3D
+	// CellsZ is the number of planes in the grid of a three-dimensional automaton. See Automaton3D.
+	CellsZ uint
+	// SliceZ is the plane of a three-dimensional automaton shown when the window opens. See Automaton3D.
+	SliceZ uint
+	// InitialGrid3D optionally defines the initial state of each cell of a three-dimensional automaton, indexed as InitialGrid3D[z][x][y].
+	// If set, it must be CellsX by CellsY by CellsZ, and it takes precedence over InitialState.
+	InitialGrid3D model.Grid3D
+	// Unbounded denotes whether to simulate Automaton on an unbounded [model.Universe], rather than a CellsX by CellsY grid, so patterns such as guns and puffers can grow without limit.
+	// Automaton must declare its locality with [model.Automaton.SetLocality]. Every cell starts in InitialState, which must stay put when surrounded by itself, and InitialGrid, if set, is placed with its bottom left corner at the origin.
+	// The window shows a CellsX by CellsY viewport of the universe, starting at the origin. Pressing the arrow keys pans the viewport by an eighth of its size.
+	//
+	// Edit mode and GridFile work on the cells in the viewport. Spacetime, Automaton3D and Observers are not supported for unbounded simulations.
+	Unbounded bool
+	// Rewind is the number of past generations kept while the simulation runs, so they can be viewed again. If 0, no history is kept.
+	//
+	// While the simulation runs, pressing comma pauses it and steps back a generation, and N or . steps forward again. While paused, the timeline across the bottom of the window
+	// spans the generations kept, and clicking or dragging on it scrubs through them. Resuming from a past generation forgets the generations after it, and carries on from there.
+	// Rewind is not supported for three-dimensional automata or unbounded simulations.
+	Rewind uint
+	// Observers are notified of every generation once the simulation starts. See [simulation.Observer].
+	// Whenever the simulation is reset or restarted from edit mode, they are registered again, and see it from generation 0, with previous set to nil.
+	// Each registration starts a new run, which a [github.com/michael-ryan/cellularautomata/v2/stats.Recorder] numbers in its records.
+	// When the simulation resumes from a past generation, those that implement [simulation.Rewinder] are told of it, so they can forget the generations after it.
+	//
+	// To record how the population of each state changes over time, use a [github.com/michael-ryan/cellularautomata/v2/stats.Recorder], and export its records once Launch returns.
+	Observers []simulation.Observer
 }
 
 // canvas represents a grid of virtual pixels (i.e. cells) for our simulation, as it is unlikely we want every single real pixel to be simulated as a cell
@@ -40,6 +101,10 @@ type canvas struct {
 	RealWidth, RealHeight uint
 	// cell dimensions in pixels
 	CellWidth, CellHeight uint
+	// Hexagonal denotes whether odd rows are drawn offset by half a cell, for automata with a [model.Hexagonal] topology
+	Hexagonal bool
+	// View is the part of the canvas shown in the window, which the user can zoom and pan
+	View viewport
 }
 
 var configChan chan Config = make(chan Config, 1)
@@ -55,11 +120,84 @@ func Launch(config Config) error {
 		return fmt.Errorf("cellsY (%v) cannot be larger than windowY (%v), since each cell requires at least one pixel", config.CellsY, config.WindowY)
 	}
 
-	stateCount := len(config.Automaton.GetColouring())
+	if config.Automaton3D == nil && config.Automaton.GetGraph() != nil {
+		return fmt.Errorf("automaton is simulated on a graph, which cannot be drawn on a grid, see the network package to run it headlessly")
+	}
+
+	stateCount := int(config.countStates())
 	if int(config.InitialState) >= stateCount {
 		return fmt.Errorf("initialState too high at %v, there are only %v states defined, so initialState is bounded by [0-%v]", config.InitialState, stateCount, stateCount-1)
 	}
 
+	rows := config.CellsY
+	if config.Spacetime {
+		rows = 1
+	}
+
+	if config.InitialGrid != nil && !config.Unbounded {
+		if err := validateGrid("initialGrid", config.InitialGrid, config.CellsX, rows, stateCount); err != nil {
+			return err
+		}
+	}
+
+	if config.Unbounded {
+		if config.Spacetime || config.Automaton3D != nil {
+			return fmt.Errorf("unbounded simulations only support two-dimensional automata")
+		}
+
+		if len(config.Observers) > 0 {
+			return fmt.Errorf("observers are not supported for unbounded simulations")
+		}
+
+		if config.Rewind > 0 {
+			return fmt.Errorf("rewind is not supported for unbounded simulations")
+		}
+
+		if config.InitialGrid != nil {
+			height := uint(0)
+			if len(config.InitialGrid) > 0 {
+				height = uint(len(config.InitialGrid[0]))
+			}
+			if err := validateGrid("initialGrid", config.InitialGrid, uint(len(config.InitialGrid)), height, stateCount); err != nil {
+				return err
+			}
+		}
+
+		if _, err := model.NewUniverse(config.Automaton, config.InitialState); err != nil {
+			return err
+		}
+	}
+
+	if config.Automaton3D != nil {
+		if config.Spacetime {
+			return fmt.Errorf("spacetime rendering is not supported for three-dimensional automata")
+		}
+
+		if len(config.Observers) > 0 {
+			return fmt.Errorf("observers are not supported for three-dimensional automata")
+		}
+
+		if config.Rewind > 0 {
+			return fmt.Errorf("rewind is not supported for three-dimensional automata")
+		}
+
+		if config.SliceZ >= config.CellsZ {
+			return fmt.Errorf("sliceZ (%v) must be less than cellsZ (%v)", config.SliceZ, config.CellsZ)
+		}
+
+		if config.InitialGrid3D != nil {
+			if uint(len(config.InitialGrid3D)) != config.CellsZ {
+				return fmt.Errorf("initialGrid3D has depth %v, but cellsZ is %v", len(config.InitialGrid3D), config.CellsZ)
+			}
+
+			for z, plane := range config.InitialGrid3D {
+				if err := validateGrid(fmt.Sprintf("initialGrid3D plane %v", z), plane, config.CellsX, config.CellsY, stateCount); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
 	// dirty hack to get parameters into the opengl.Run callback
 	configChan <- config
 	opengl.Run(launch)
@@ -67,6 +205,35 @@ func Launch(config Config) error {
 	return nil
 }
 
+// validateGrid checks that grid, described by name in errors, is width by height and only holds states below stateCount.
+func validateGrid(name string, grid [][]uint, width, height uint, stateCount int) error {
+	if uint(len(grid)) != width {
+		return fmt.Errorf("%v has width %v, but it must be %v", name, len(grid), width)
+	}
+
+	for x, column := range grid {
+		if uint(len(column)) != height {
+			return fmt.Errorf("%v column %v has height %v, but it must be %v", name, x, len(column), height)
+		}
+
+		for y, state := range column {
+			if int(state) >= stateCount {
+				return fmt.Errorf("%v cell (%v, %v) has state %v, but there are only %v states defined, so states are bounded by [0-%v]", name, x, y, state, stateCount, stateCount-1)
+			}
+		}
+	}
+
+	return nil
+}
+
+// countStates returns the number of states of whichever automaton is being simulated.
+func (c Config) countStates() uint {
+	if c.Automaton3D != nil {
+		return c.Automaton3D.CountStates()
+	}
+	return c.Automaton.CountStates()
+}
+
 func launch() {
 	config := <-configChan
 
@@ -85,41 +252,433 @@ func launch() {
 	fpsClock := time.NewTicker(frameDuration)
 
 	canvas := newCanvas(config.CellsX, config.CellsY, config.WindowX, config.WindowY, config.InitialState)
+	canvas.Hexagonal = config.Automaton3D == nil && !config.Spacetime && config.Automaton.GetTopology() == model.Hexagonal
+
+	if config.Automaton3D != nil {
+		launch3D(win, fpsClock, canvas, config)
+		return
+	}
 
-	started := config.SkipEditor
+	if config.Unbounded {
+		launchUnbounded(win, fpsClock, canvas, config)
+		return
+	}
+
+	// grid holds the cells being simulated. Unless drawing a spacetime diagram, these are the canvas cells themselves
+	grid := canvas.Cells
+	var history *spacetime
+	if config.Spacetime {
+		grid = model.NewGrid(config.CellsX, 1, config.InitialState)
+		history = newSpacetime(canvas.Cells, config.InitialState)
+	}
+	for x := range config.InitialGrid {
+		copy(grid[x], config.InitialGrid[x])
+	}
+	if history != nil {
+		history.reset(grid)
+	}
+
+	controls := newPlayback(config.Fps, fpsClock)
+	edit := newEditMode(config)
+	editing := !config.SkipEditor
+	title := &titleBar{win: win}
+	// initial is the grid the simulation last started from, which it returns to when reset
+	var initial [][]uint
+	var sim *simulation.Simulation
+	// cursor is the generation being shown, which is behind the simulation while scrubbing back through its history
+	var cursor uint
+	bar := timeline{realWidth: config.WindowX}
+	start := func() {
+		sim, err = simulation.New(config.Automaton, grid)
+		if err != nil {
+			panic(err)
+		}
+		sim.SetHistory(config.Rewind)
+		for _, o := range config.Observers {
+			sim.Observe(o)
+		}
+		cursor = 0
+	}
+	if !editing {
+		initial = cloneGrid(grid)
+		start()
+	}
+	set := func(p editor.Point, state uint) {
+		grid[p.X][p.Y] = state
+	}
+	cellsAt := func(generation uint) [][]uint {
+		cells, err := sim.CellsAt(generation)
+		if err != nil {
+			panic(err)
+		}
+		return cells
+	}
+	// rewind forgets the generations after the one being shown, so the simulation carries on from there
+	rewind := func() {
+		if err := sim.Rewind(cursor); err != nil {
+			panic(err)
+		}
+	}
 
 	for range fpsClock.C {
 		if win.Closed() {
 			return
 		}
 
-		if !started {
-			started = preStart(win, canvas, config.Automaton.CountStates())
+		canvas.handleView(win)
+
+		// advanced is set if the generation after the one shown before is now shown, and jumped if any other grid is
+		advanced, jumped := false, false
+		if editing {
+			edit.handleHistory(win)
+			if preStart(win, canvas, grid, config, edit, set) {
+				editing = false
+				initial = cloneGrid(grid)
+				start()
+				controls.start()
+				jumped = true
+			}
 		} else {
-			canvas.Cells = config.Automaton.Step(canvas.Cells)
+			action := controls.handle(win)
+
+			if controls.paused && win.Pressed(pixel.MouseButtonLeft) {
+				if generation, ok := bar.at(win.MousePosition(), sim.Earliest(), sim.Generation()); ok && generation != cursor {
+					cursor = generation
+					jumped = true
+				}
+			}
+
+			switch action {
+			case actionStep:
+				if controls.paused && cursor < sim.Generation() {
+					cursor++
+				} else {
+					rewind()
+					sim.Step()
+					cursor = sim.Generation()
+				}
+				advanced = true
+			case actionBack:
+				if cursor > sim.Earliest() {
+					cursor--
+					jumped = true
+				}
+			case actionReset:
+				grid = cloneGrid(initial)
+				start()
+				jumped = true
+			case actionEdit:
+				rewind()
+				// the run is recorded as an action, so undoing it goes back to where the simulation started
+				edit.history.Record(editor.Edit{Changes: editor.Diff(initial, grid), Set: set})
+				editing = true
+				sim = nil
+			}
+		}
+
+		if advanced || jumped {
+			grid = cellsAt(cursor)
+		}
+
+		if history == nil {
+			canvas.Cells = grid
+		} else if sim == nil {
+			history.reset(grid)
+		} else if jumped {
+			// redraw the diagram up to the generation being shown, from as far back as fits and has been kept
+			from := cursor - min(cursor-sim.Earliest(), canvas.Height-1)
+			history.reset(cellsAt(from))
+			for generation := from + 1; generation <= cursor; generation++ {
+				history.push(cellsAt(generation))
+			}
+		} else if advanced {
+			history.push(grid)
+		}
+
+		var scrubber func(pixels []uint8)
+		if sim != nil && controls.paused && config.Rewind > 0 {
+			earliest, latest, shown := sim.Earliest(), sim.Generation(), cursor
+			scrubber = func(pixels []uint8) {
+				bar.draw(pixels, earliest, shown, latest)
+			}
 		}
 
-		renderFrame(win, canvas, config.Automaton.GetColouring())
+		title.set(windowTitle("", edit, editing))
+		renderFrame(win, canvas, config.Automaton.GetColouring(), edit.overlay(editing, config.Automaton.GetColouring()), scrubber)
 	}
 }
 
-func preStart(win *opengl.Window, canvas canvas, stateCount uint) bool {
+// preStart handles input in edit mode, where grid holds the initial cells, and reports whether the simulation should start.
+// set sets a cell of whatever grid is showing, and is used to undo and redo changes.
+func preStart(win *opengl.Window, canvas canvas, grid [][]uint, config Config, edit *editMode, set func(p editor.Point, state uint)) bool {
 	if win.JustPressed(pixel.KeyS) {
+		edit.finish()
 		return true
 	}
 
-	if win.JustPressed(pixel.MouseButton1) {
-		location := getVirtualPixelXY(win.MousePosition(), canvas)
-		oldCell := canvas.Cells[uint(location.X)][uint(location.Y)]
-		newCell := (oldCell + 1) % stateCount
-		canvas.Cells[uint(location.X)][uint(location.Y)] = newCell
+	if config.GridFile != "" && win.JustPressed(pixel.KeyW) {
+		if err := rle.WriteFile(config.GridFile, grid, ""); err != nil {
+			log.Printf("failed to save grid: %v", err)
+		}
 	}
 
+	if config.GridFile != "" && win.JustPressed(pixel.KeyL) {
+		edit.finish()
+		before := cloneGrid(grid)
+		if err := loadGrid(grid, config); err != nil {
+			log.Printf("failed to load grid: %v", err)
+		}
+		edit.history.Record(editor.Edit{Changes: editor.Diff(before, grid), Set: set})
+	}
+
+	edit.handleKeys(win)
+	edit.handleMouse(win, canvas, grid, config.Spacetime, set)
+
 	return false
 }
 
-func renderFrame(win *opengl.Window, canvas canvas, colourings []model.Rgb) {
-	win.Canvas().SetPixels(canvas.paint(colourings))
+// loadGrid replaces the cells of grid with the pattern in config.GridFile, centred on a background of config.InitialState.
+// The grid is left untouched if the pattern cannot be loaded.
+func loadGrid(grid [][]uint, config Config) error {
+	pattern, err := rle.ReadFile(config.GridFile)
+	if err != nil {
+		return err
+	}
+
+	stateCount := config.countStates()
+	for x := range pattern.Cells {
+		for y, state := range pattern.Cells[x] {
+			if state >= stateCount {
+				return fmt.Errorf("cell (%v, %v) of %v has state %v, but there are only %v states defined", x, y, config.GridFile, state, stateCount)
+			}
+		}
+	}
+
+	width, height := uint(len(grid)), uint(len(grid[0]))
+	loaded := model.NewGrid(width, height, config.InitialState)
+	offsetX := (int(width) - int(pattern.Width())) / 2
+	offsetY := (int(height) - int(pattern.Height())) / 2
+	if err := pattern.Place(loaded, offsetX, offsetY); err != nil {
+		return err
+	}
+
+	for x := range loaded {
+		copy(grid[x], loaded[x])
+	}
+
+	return nil
+}
+
+// launch3D runs the simulation of a three-dimensional automaton, rendering one plane of it at a time.
+func launch3D(win *opengl.Window, fpsClock *time.Ticker, canvas canvas, config Config) {
+	grid := model.NewGrid3D(config.CellsX, config.CellsY, config.CellsZ, config.InitialState)
+	copyGrid3D(grid, config.InitialGrid3D)
+	next := model.NewGrid3D(config.CellsX, config.CellsY, config.CellsZ, 0)
+	// initial is the grid the simulation last started from, which it returns to when reset
+	initial := model.NewGrid3D(config.CellsX, config.CellsY, config.CellsZ, 0)
+	copyGrid3D(initial, grid)
+
+	z := config.SliceZ
+
+	controls := newPlayback(config.Fps, fpsClock)
+	edit := newEditMode(config)
+	editing := !config.SkipEditor
+	title := &titleBar{win: win}
+	generation := uint(0)
+	// planeSet returns a function setting cells of plane z of the grid
+	planeSet := func(z uint) func(p editor.Point, state uint) {
+		return func(p editor.Point, state uint) {
+			grid[z][p.X][p.Y] = state
+		}
+	}
+
+	for range fpsClock.C {
+		if win.Closed() {
+			return
+		}
+
+		canvas.handleView(win)
+
+		if win.JustPressed(pixel.KeyUp) && z+1 < config.CellsZ {
+			edit.finish()
+			z++
+		}
+		if win.JustPressed(pixel.KeyDown) && z > 0 {
+			edit.finish()
+			z--
+		}
+
+		if editing {
+			edit.handleHistory(win)
+			if preStart(win, canvas, grid.Plane(z), config, edit, planeSet(z)) {
+				editing = false
+				copyGrid3D(initial, grid)
+				generation = 0
+				controls.start()
+			}
+		} else {
+			switch controls.handle(win) {
+			case actionStep:
+				config.Automaton3D.StepInto(grid, next, generation)
+				grid, next = next, grid
+				generation++
+			case actionReset:
+				copyGrid3D(grid, initial)
+				generation = 0
+			case actionEdit:
+				// the run is recorded as an action, so undoing it goes back to where the simulation started
+				var edits []editor.Edit
+				for plane := range config.CellsZ {
+					edits = append(edits, editor.Edit{Changes: editor.Diff(initial.Plane(plane), grid.Plane(plane)), Set: planeSet(plane)})
+				}
+				edit.history.Record(edits...)
+				editing = true
+			}
+		}
+
+		canvas.Cells = grid.Plane(z)
+		title.set(windowTitle(fmt.Sprintf("z = %v", z), edit, editing))
+		renderFrame(win, canvas, config.Automaton3D.GetColouring(), edit.overlay(editing, config.Automaton3D.GetColouring()))
+	}
+}
+
+// launchUnbounded runs the simulation on an unbounded universe, rendering the part of it in a viewport that the arrow keys pan around.
+func launchUnbounded(win *opengl.Window, fpsClock *time.Ticker, canvas canvas, config Config) {
+	universe, err := model.NewUniverse(config.Automaton, config.InitialState)
+	if err != nil {
+		panic(err)
+	}
+	if err := universe.SetRegion(config.InitialGrid, 0, 0); err != nil {
+		panic(err)
+	}
+
+	// the viewport pans by an eighth of its size, and vertically by an even number of rows, so hexagonal rows keep their offsets
+	panX := max(int(config.CellsX)/8, 1)
+	panY := max(int(config.CellsY)/16*2, 2)
+	viewX, viewY := 0, 0
+
+	controls := newPlayback(config.Fps, fpsClock)
+	edit := newEditMode(config)
+	editing := !config.SkipEditor
+	title := &titleBar{win: win}
+	// initial holds the cells the simulation last started from, with its bottom left corner at (initialX, initialY), which it returns to when reset
+	var initial [][]uint
+	var initialX, initialY int
+	snapshot := func() {
+		x, y, width, height, _ := universe.Bounds()
+		initial, initialX, initialY = universe.Region(x, y, width, height), x, y
+	}
+	if !editing {
+		snapshot()
+	}
+	// viewSet returns a function setting cells of the universe, relative to (x, y)
+	viewSet := func(x, y int) func(p editor.Point, state uint) {
+		return func(p editor.Point, state uint) {
+			universe.Set(x+p.X, y+p.Y, state)
+		}
+	}
+	// recordRun records the changes since the simulation started as an action, so undoing it goes back to where the simulation started
+	recordRun := func() {
+		x, y, width, height, ok := universe.Bounds()
+		if !ok {
+			x, y = initialX, initialY
+		}
+		if initialWidth := len(initial); initialWidth > 0 {
+			right, top := max(x+int(width), initialX+initialWidth), max(y+int(height), initialY+len(initial[0]))
+			x, y = min(x, initialX), min(y, initialY)
+			width, height = uint(right-x), uint(top-y)
+		}
+
+		before := model.NewGrid(width, height, config.InitialState)
+		for ix := range initial {
+			copy(before[initialX-x+ix][initialY-y:], initial[ix])
+		}
+		edit.history.Record(editor.Edit{Changes: editor.Diff(before, universe.Region(x, y, width, height)), Set: viewSet(x, y)})
+	}
+
+	for range fpsClock.C {
+		if win.Closed() {
+			return
+		}
+
+		canvas.handleView(win)
+
+		moved := true
+		switch {
+		case win.JustPressed(pixel.KeyLeft):
+			viewX -= panX
+		case win.JustPressed(pixel.KeyRight):
+			viewX += panX
+		case win.JustPressed(pixel.KeyDown):
+			viewY -= panY
+		case win.JustPressed(pixel.KeyUp):
+			viewY += panY
+		default:
+			moved = false
+		}
+		if moved {
+			// a stroke's shape is redrawn relative to where it started, which has now moved
+			edit.finish()
+		}
+
+		if editing {
+			// undoing changes the universe directly, so must happen before the viewport is copied
+			edit.handleHistory(win)
+
+			// edits are made to a copy of the viewport, and written back to the universe
+			grid := universe.Region(viewX, viewY, config.CellsX, config.CellsY)
+			started := preStart(win, canvas, grid, config, edit, viewSet(viewX, viewY))
+			if err := universe.SetRegion(grid, viewX, viewY); err != nil {
+				panic(err)
+			}
+			if started {
+				editing = false
+				snapshot()
+				controls.start()
+			}
+		} else {
+			switch controls.handle(win) {
+			case actionStep:
+				universe.Step()
+			case actionReset:
+				universe, err = model.NewUniverse(config.Automaton, config.InitialState)
+				if err != nil {
+					panic(err)
+				}
+				if err := universe.SetRegion(initial, initialX, initialY); err != nil {
+					panic(err)
+				}
+			case actionEdit:
+				recordRun()
+				editing = true
+			}
+		}
+
+		canvas.Cells = universe.Region(viewX, viewY, config.CellsX, config.CellsY)
+		title.set(windowTitle(fmt.Sprintf("%v, %v", viewX, viewY), edit, editing))
+		renderFrame(win, canvas, config.Automaton.GetColouring(), edit.overlay(editing, config.Automaton.GetColouring()))
+	}
+}
+
+// copyGrid3D copies every cell of src into dst, which must have the same dimensions.
+func copyGrid3D(dst, src model.Grid3D) {
+	for z := range src {
+		for x := range src[z] {
+			copy(dst[z][x], src[z][x])
+		}
+	}
+}
+
+// renderFrame draws the canvas to the window, followed by each overlay that is not nil, such as the palette in edit mode.
+func renderFrame(win *opengl.Window, canvas canvas, colourings []model.Rgb, overlays ...func(pixels []uint8)) {
+	pixels := canvas.paint(colourings)
+	for _, overlay := range overlays {
+		if overlay != nil {
+			overlay(pixels)
+		}
+	}
+
+	win.Canvas().SetPixels(pixels)
 	win.Update()
 }
 
@@ -153,15 +712,38 @@ func newCanvas(width, height, realWidth, realHeight, initialState uint) canvas {
 	c.CellWidth = c.RealWidth / c.Width
 	c.CellHeight = c.RealHeight / c.Height
 
+	c.View = viewport{Zoom: 1}
+
 	return c
 }
 
 func (c canvas) paint(colourings []model.Rgb) []uint8 {
 	pixels := make([]float64, 4*c.RealWidth*c.RealHeight)
 
-	for x := range c.Width {
-		for y := range c.Height {
-			pixels = setPixel(pixels, uint(x), uint(y), colourings[c.Cells[x][y]], c)
+	if c.View == (viewport{Zoom: 1}) {
+		for x := range c.Width {
+			for y := range c.Height {
+				pixels = setPixel(pixels, uint(x), uint(y), colourings[c.Cells[x][y]], c)
+			}
+		}
+
+		return premultiply(pixels)
+	}
+
+	// when zoomed or panned, each real pixel takes the colour of the cell under its centre
+	for realY := range c.RealHeight {
+		for realX := range c.RealWidth {
+			location, ok := c.cellAt(c.View.toCanvas(pixel.Vec{X: float64(realX) + 0.5, Y: float64(realY) + 0.5}))
+			if !ok {
+				continue
+			}
+
+			colour := colourings[c.Cells[uint(location.X)][uint(location.Y)]]
+			redIndex := getRealPixelIndex(realX, realY, c.RealWidth)
+			pixels[redIndex] = colour.R
+			pixels[redIndex+1] = colour.G
+			pixels[redIndex+2] = colour.B
+			pixels[redIndex+3] = 1
 		}
 	}
 
@@ -169,8 +751,9 @@ func (c canvas) paint(colourings []model.Rgb) []uint8 {
 }
 
 func setPixel(pixels []float64, x, y uint, colour model.Rgb, c canvas) []float64 {
-	pixelLeftBound := c.CellWidth * x
-	pixelRightBound := pixelLeftBound + c.CellWidth - 1
+	pixelLeftBound := c.CellWidth*x + c.rowOffset(y)
+	// offset rows lose the right half of their last cell off the edge of the canvas
+	pixelRightBound := min(pixelLeftBound+c.CellWidth, c.RealWidth) - 1
 	pixelLowerBound := c.CellHeight * y
 	pixelUpperBound := pixelLowerBound + c.CellHeight - 1
 
@@ -187,10 +770,37 @@ func setPixel(pixels []float64, x, y uint, colour model.Rgb, c canvas) []float64
 	return pixels
 }
 
-func getVirtualPixelXY(xy pixel.Vec, c canvas) pixel.Vec {
-	x := uint(xy.X) / c.CellWidth
+// getVirtualPixelXY finds the cell under the real pixel xy of the window, through the canvas' viewport.
+// It reports false if there is no cell there, such as in the gap left by an offset row.
+func getVirtualPixelXY(xy pixel.Vec, c canvas) (pixel.Vec, bool) {
+	return c.cellAt(c.View.toCanvas(xy))
+}
+
+// cellAt finds the cell at the point xy of the whole canvas, in real pixels as if the canvas were shown unzoomed. It reports false if there is no cell there.
+func (c canvas) cellAt(xy pixel.Vec) (pixel.Vec, bool) {
+	if xy.X < 0 || xy.Y < 0 {
+		return pixel.Vec{}, false
+	}
+
 	y := uint(xy.Y) / c.CellHeight
-	return pixel.Vec{X: float64(x), Y: float64(y)}
+	if y >= c.Height || uint(xy.X) < c.rowOffset(y) {
+		return pixel.Vec{}, false
+	}
+
+	x := (uint(xy.X) - c.rowOffset(y)) / c.CellWidth
+	if x >= c.Width {
+		return pixel.Vec{}, false
+	}
+
+	return pixel.Vec{X: float64(x), Y: float64(y)}, true
+}
+
+// rowOffset returns the number of real pixels that row y is shifted to the right by. On a hexagonal canvas, odd rows are shifted by half a cell.
+func (c canvas) rowOffset(y uint) uint {
+	if c.Hexagonal && y%2 == 1 {
+		return c.CellWidth / 2
+	}
+	return 0
 }
 
 func getRealPixelIndex(realX, realY, canvasWidth uint) uint {
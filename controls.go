@@ -0,0 +1,92 @@
+package cellularautomata
+
+import (
+	"time"
+
+	"github.com/gopxl/pixel/v2"
+	"github.com/gopxl/pixel/v2/backends/opengl"
+)
+
+// maxFps is the fastest speed the simulation can be set to from the keyboard.
+const maxFps = 1024
+
+// playbackAction is what the user has asked a running simulation to do in a frame.
+type playbackAction int
+
+const (
+	// actionNone leaves the simulation as it is, e.g. because it is paused.
+	actionNone playbackAction = iota
+	// actionStep advances the simulation by a single generation.
+	actionStep
+	// actionReset returns the simulation to the grid it started from.
+	actionReset
+	// actionEdit returns to edit mode, with the current grid.
+	actionEdit
+	// actionBack shows the generation before the one being shown, if it has been kept.
+	actionBack
+)
+
+// playback handles the keyboard controls available once a simulation has started:
+// space pauses and resumes, N or . advances a single generation while paused, comma steps back a generation and pauses, + and - double and halve the speed,
+// R resets to the grid the simulation started from, and E returns to edit mode.
+type playback struct {
+	paused bool
+	fps    uint
+	clock  *time.Ticker
+}
+
+func newPlayback(fps uint, clock *time.Ticker) *playback {
+	return &playback{fps: fps, clock: clock}
+}
+
+// start is called whenever the simulation leaves edit mode, and resumes it if it was paused.
+func (p *playback) start() {
+	p.paused = false
+}
+
+// handle reads the keyboard for a single frame, and returns what the simulation should do.
+func (p *playback) handle(win *opengl.Window) playbackAction {
+	if win.JustPressed(pixel.KeySpace) {
+		p.paused = !p.paused
+	}
+
+	// a speed already above maxFps, set in the Config, is never lowered by speeding up
+	if (win.JustPressed(pixel.KeyEqual) || win.JustPressed(pixel.KeyKPAdd)) && p.fps < maxFps {
+		p.setFps(min(p.fps*2, maxFps))
+	}
+	if win.JustPressed(pixel.KeyMinus) || win.JustPressed(pixel.KeyKPSubtract) {
+		p.setFps(max(p.fps/2, 1))
+	}
+
+	switch {
+	case win.JustPressed(pixel.KeyR):
+		return actionReset
+	case win.JustPressed(pixel.KeyE):
+		return actionEdit
+	case win.JustPressed(pixel.KeyComma):
+		p.paused = true
+		return actionBack
+	case !p.paused || win.JustPressed(pixel.KeyN) || win.JustPressed(pixel.KeyPeriod):
+		return actionStep
+	}
+	return actionNone
+}
+
+// setFps changes the speed of the simulation to fps steps per second.
+func (p *playback) setFps(fps uint) {
+	if fps == p.fps {
+		return
+	}
+	p.fps = fps
+	p.clock.Reset(time.Second / time.Duration(fps))
+}
+
+// cloneGrid returns a copy of grid, indexed as grid[x][y].
+func cloneGrid(grid [][]uint) [][]uint {
+	clone := make([][]uint, len(grid))
+	for x := range grid {
+		clone[x] = make([]uint, len(grid[x]))
+		copy(clone[x], grid[x])
+	}
+	return clone
+}
@@ -0,0 +1,227 @@
+package cellularautomata
+
+import (
+	"fmt"
+
+	"github.com/gopxl/pixel/v2"
+	"github.com/gopxl/pixel/v2/backends/opengl"
+	"github.com/michael-ryan/cellularautomata/v2/editor"
+	"github.com/michael-ryan/cellularautomata/v2/model"
+)
+
+// maxSwatch is the largest width and height of a swatch in the palette, in real pixels.
+const maxSwatch = 24
+
+// historyLimit is the number of actions that can be undone in edit mode.
+const historyLimit = 256
+
+// editMode holds the state of edit mode that lasts between frames, and between visits to edit mode.
+type editMode struct {
+	editor  *editor.Editor
+	history *editor.History
+	// strokeSet sets a cell of whatever the stroke in progress is drawing on, for recording it in the history
+	strokeSet func(p editor.Point, state uint)
+	// palette is the palette of states drawn across the top of the window, if it is being shown
+	palette     palette
+	showPalette bool
+}
+
+func newEditMode(config Config) *editMode {
+	e, err := editor.New(config.countStates(), config.InitialState)
+	if err != nil {
+		panic(err)
+	}
+
+	return &editMode{
+		editor:      e,
+		history:     editor.NewHistory(historyLimit),
+		palette:     newPalette(config.countStates(), config.WindowX, config.WindowY),
+		showPalette: true,
+	}
+}
+
+// status describes the tool, state and brush size being used, for the window title.
+func (m *editMode) status() string {
+	return fmt.Sprintf("editing: %v, state %v, size %v", m.editor.GetTool(), m.editor.GetState(), m.editor.GetBrushSize())
+}
+
+// handleKeys chooses the tool, state and brush size, and shows or hides the palette, from the keyboard.
+func (m *editMode) handleKeys(win *opengl.Window) {
+	for digit := range uint(10) {
+		if win.JustPressed(pixel.Key0 + pixel.Button(digit)) {
+			// states beyond the number keys can only be picked from the palette
+			m.editor.SetState(digit)
+		}
+	}
+
+	if win.JustPressed(pixel.KeyTab) {
+		m.editor.SetTool(m.editor.GetTool().Next())
+	}
+
+	size := m.editor.GetBrushSize()
+	if win.JustPressed(pixel.KeyLeftBracket) && size > 1 {
+		m.editor.SetBrushSize(size - 1)
+	}
+	if win.JustPressed(pixel.KeyRightBracket) && size < editor.MaxBrushSize {
+		m.editor.SetBrushSize(size + 1)
+	}
+
+	if win.JustPressed(pixel.KeyP) {
+		m.showPalette = !m.showPalette
+	}
+}
+
+// finish finishes the stroke in progress, if there is one, and records it in the history as a single action.
+func (m *editMode) finish() {
+	if changes := m.editor.Release(); changes != nil {
+		m.history.Record(editor.Edit{Changes: changes, Set: m.strokeSet})
+	}
+}
+
+// handleHistory undoes the last action on Ctrl+Z, and redoes the last undone action on Ctrl+Y or Ctrl+Shift+Z.
+func (m *editMode) handleHistory(win *opengl.Window) {
+	if !win.Pressed(pixel.KeyLeftControl) && !win.Pressed(pixel.KeyRightControl) {
+		return
+	}
+
+	shift := win.Pressed(pixel.KeyLeftShift) || win.Pressed(pixel.KeyRightShift)
+	switch {
+	case win.JustPressed(pixel.KeyZ) && !shift:
+		m.finish()
+		m.history.Undo()
+	case win.JustPressed(pixel.KeyY), win.JustPressed(pixel.KeyZ) && shift:
+		m.finish()
+		m.history.Redo()
+	}
+}
+
+// handleMouse paints on grid with the left mouse button, and erases with the right. Clicking on the palette picks a state instead.
+// If row is true, every click and drag is moved onto the bottom row, as the grid holds a single row of a spacetime diagram.
+// set sets a cell of whatever grid is showing, and is used to undo and redo strokes.
+func (m *editMode) handleMouse(win *opengl.Window, canvas canvas, grid [][]uint, row bool, set func(p editor.Point, state uint)) {
+	cellAt := func() (editor.Point, bool) {
+		location, ok := getVirtualPixelXY(win.MousePosition(), canvas)
+		p := editor.Point{X: int(location.X), Y: int(location.Y)}
+		if row {
+			p.Y = 0
+		}
+		return p, ok
+	}
+
+	paint, erase := win.JustPressed(pixel.MouseButtonLeft), win.JustPressed(pixel.MouseButtonRight)
+	if paint || erase {
+		if state, ok := m.palette.at(win.MousePosition()); ok && m.showPalette {
+			if paint {
+				m.editor.SetState(state)
+			}
+			return
+		}
+
+		if p, ok := cellAt(); ok {
+			m.finish()
+			m.strokeSet = set
+			m.editor.Press(grid, p, erase)
+		}
+		return
+	}
+
+	if !m.editor.Drawing() {
+		return
+	}
+
+	if !win.Pressed(pixel.MouseButtonLeft) && !win.Pressed(pixel.MouseButtonRight) {
+		m.finish()
+		return
+	}
+
+	if p, ok := cellAt(); ok {
+		m.editor.Drag(grid, p)
+	}
+}
+
+// overlay returns a function drawing the palette over a frame, or nil if it is not being shown.
+func (m *editMode) overlay(editing bool, colourings []model.Rgb) func(pixels []uint8) {
+	if !editing || !m.showPalette {
+		return nil
+	}
+	return func(pixels []uint8) {
+		m.palette.draw(pixels, colourings, m.editor.GetState())
+	}
+}
+
+// palette is a row of swatches, one per state, drawn across the top left of the window in edit mode.
+type palette struct {
+	states uint
+	// swatch is the width and height of each swatch, in real pixels
+	swatch uint
+	// realWidth and realHeight are the dimensions of the window, in real pixels
+	realWidth, realHeight uint
+}
+
+func newPalette(states, realWidth, realHeight uint) palette {
+	swatch := max(min(maxSwatch, realWidth/states, realHeight), 1)
+	return palette{states: states, swatch: swatch, realWidth: realWidth, realHeight: realHeight}
+}
+
+// at returns the state whose swatch is under the real pixel xy, reporting false if there is none.
+func (p palette) at(xy pixel.Vec) (uint, bool) {
+	if xy.X < 0 || xy.Y < 0 {
+		return 0, false
+	}
+
+	x, fromTop := uint(xy.X), p.realHeight-1-min(uint(xy.Y), p.realHeight-1)
+	if fromTop >= p.swatch || x/p.swatch >= p.states {
+		return 0, false
+	}
+	return x / p.swatch, true
+}
+
+// draw paints the palette over pixels, a premultiplied frame as returned by [canvas.paint], outlining the swatch of the active state.
+func (p palette) draw(pixels []uint8, colourings []model.Rgb, active uint) {
+	for state := range min(p.states, p.realWidth/p.swatch) {
+		colour := colourings[state]
+		// the active state is outlined in the inverse of its colour, so the outline shows up against it
+		outline, thickness := model.Rgb{R: 0.5, G: 0.5, B: 0.5}, uint(1)
+		if state == active {
+			outline, thickness = model.Rgb{R: 1 - colour.R, G: 1 - colour.G, B: 1 - colour.B}, max(p.swatch/8, 2)
+		}
+
+		left, top := state*p.swatch, p.realHeight-1
+		for dx := range p.swatch {
+			for dy := range p.swatch {
+				c := colour
+				if dx < thickness || dy < thickness || dx >= p.swatch-thickness || dy >= p.swatch-thickness {
+					c = outline
+				}
+
+				i := getRealPixelIndex(left+dx, top-dy, p.realWidth)
+				pixels[i], pixels[i+1], pixels[i+2], pixels[i+3] = uint8(c.R*255), uint8(c.G*255), uint8(c.B*255), 255
+			}
+		}
+	}
+}
+
+// windowTitle returns the title of the window, showing location if it is not empty, and what is being drawn with while editing.
+func windowTitle(location string, edit *editMode, editing bool) string {
+	title := "Cellular Automata"
+	if location != "" {
+		title += " (" + location + ")"
+	}
+	if editing {
+		title += " - " + edit.status()
+	}
+	return title
+}
+
+// titleBar sets the title of the window, skipping calls that would not change it.
+type titleBar struct {
+	win     *opengl.Window
+	current string
+}
+
+func (t *titleBar) set(title string) {
+	if title != t.current {
+		t.win.SetTitle(title)
+		t.current = title
+	}
+}
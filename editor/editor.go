@@ -0,0 +1,316 @@
+// Package editor provides the drawing tools of the GUI's edit mode, working on grids indexed as cells[x][y].
+//
+// The functions [Stamp], [DrawLine], [FillRectangle] and [FloodFill] paint directly onto a grid, clipping anything that falls outside it.
+// An [Editor] combines them into tools driven by a pointer, as in a paint program.
+package editor
+
+import (
+	"fmt"
+)
+
+// MaxBrushSize is the largest brush an [Editor] can be given.
+const MaxBrushSize = 64
+
+// Point is the location of a cell in a grid.
+type Point struct {
+	X, Y int
+}
+
+// Tool is a way of drawing with an [Editor].
+type Tool int
+
+const (
+	// Brush paints the cells under the pointer as it is dragged.
+	Brush Tool = iota
+	// Line draws a straight line from where the pointer is pressed to where it is released.
+	Line
+	// Rectangle fills the rectangle with opposite corners where the pointer is pressed and released.
+	Rectangle
+	// Fill replaces the connected region of cells sharing the state of the cell pressed.
+	Fill
+	// toolCount is the number of tools, for cycling through them.
+	toolCount
+)
+
+func (t Tool) String() string {
+	switch t {
+	case Brush:
+		return "brush"
+	case Line:
+		return "line"
+	case Rectangle:
+		return "rectangle"
+	case Fill:
+		return "fill"
+	}
+	return fmt.Sprintf("Tool(%d)", int(t))
+}
+
+// Next returns the tool after t, wrapping around to the first.
+func (t Tool) Next() Tool {
+	return (t + 1) % toolCount
+}
+
+// Editor draws on a grid with the chosen tool, state and brush size, in strokes made by pressing, dragging and releasing a pointer.
+// You should use the [New] function to create one.
+//
+// Releasing the pointer returns every change the stroke made, so the whole stroke can be recorded as one action in a [History].
+// Strokes paint with the chosen state, or erase to the background state.
+// The line and rectangle tools redraw their shape from where the stroke started every time the pointer moves, so the grid always previews the final result.
+type Editor struct {
+	tool       Tool
+	state      uint
+	background uint
+	states     uint
+	size       uint
+
+	// the stroke in progress
+	drawing bool
+	paint   uint
+	anchor  Point
+	last    Point
+	// before is the grid as it was when the stroke started, which shapes are redrawn onto
+	before [][]uint
+	// cells is the grid most recently drawn on by the stroke
+	cells [][]uint
+}
+
+// New constructs an Editor for automata with the given number of states, which erases to the background state.
+// It starts with the brush tool, a brush size of 1, and state 1 chosen, or state 0 if there is only one state.
+func New(states, background uint) (*Editor, error) {
+	if states == 0 {
+		return nil, fmt.Errorf("an editor needs at least one state")
+	}
+
+	if background >= states {
+		return nil, fmt.Errorf("background state %v invalid, there are only %v states defined (max = %v)", background, states, states-1)
+	}
+
+	return &Editor{
+		state:      min(1, states-1),
+		background: background,
+		states:     states,
+		size:       1,
+	}, nil
+}
+
+// SetTool chooses the tool used by the next stroke.
+func (e *Editor) SetTool(t Tool) error {
+	if t < 0 || t >= toolCount {
+		return fmt.Errorf("invalid tool %v", t)
+	}
+	e.tool = t
+	return nil
+}
+
+// GetTool returns the tool used by the next stroke.
+func (e *Editor) GetTool() Tool {
+	return e.tool
+}
+
+// SetState chooses the state that strokes paint with.
+func (e *Editor) SetState(state uint) error {
+	if state >= e.states {
+		return fmt.Errorf("state %v invalid, there are only %v states defined (max = %v)", state, e.states, e.states-1)
+	}
+	e.state = state
+	return nil
+}
+
+// GetState returns the state that strokes paint with.
+func (e *Editor) GetState() uint {
+	return e.state
+}
+
+// SetBrushSize sets the width and height, in cells, of the square brush used by the brush and line tools. It must be between 1 and [MaxBrushSize].
+func (e *Editor) SetBrushSize(size uint) error {
+	if size < 1 || size > MaxBrushSize {
+		return fmt.Errorf("brush size %v invalid, must be between 1 and %v", size, MaxBrushSize)
+	}
+	e.size = size
+	return nil
+}
+
+// GetBrushSize returns the width and height, in cells, of the square brush used by the brush and line tools.
+func (e *Editor) GetBrushSize() uint {
+	return e.size
+}
+
+// Drawing reports whether a stroke is in progress.
+func (e *Editor) Drawing() bool {
+	return e.drawing
+}
+
+// Press starts a stroke on cells at p, painting with the chosen state, or with the background state if erase is true.
+// Any stroke already in progress is finished first, and its changes are discarded. Call [Editor.Release] first to keep them.
+func (e *Editor) Press(cells [][]uint, p Point, erase bool) {
+	e.Release()
+
+	e.drawing = true
+	e.paint = e.state
+	if erase {
+		e.paint = e.background
+	}
+	e.anchor, e.last = p, p
+	e.before, e.cells = cloneCells(cells), cells
+
+	switch e.tool {
+	case Brush:
+		Stamp(cells, p, e.size, e.paint)
+	case Line, Rectangle:
+		e.redraw(cells, p)
+	case Fill:
+		FloodFill(cells, p, e.paint)
+	}
+}
+
+// Drag moves the pointer of the stroke in progress to p. It does nothing if no stroke is in progress.
+func (e *Editor) Drag(cells [][]uint, p Point) {
+	if !e.drawing || p == e.last {
+		return
+	}
+	e.cells = cells
+
+	switch e.tool {
+	case Brush:
+		// join up with the last point, so fast movements leave no gaps
+		DrawLine(cells, e.last, p, e.size, e.paint)
+	case Line, Rectangle:
+		e.redraw(cells, p)
+	}
+	e.last = p
+}
+
+// Release finishes the stroke in progress, leaving its shape on the grid, and returns the changes it made to the grid last drawn on.
+// It does nothing, and returns nil, if no stroke is in progress.
+func (e *Editor) Release() []Change {
+	if !e.drawing {
+		return nil
+	}
+
+	changes := Diff(e.before, e.cells)
+	e.drawing = false
+	e.before, e.cells = nil, nil
+	return changes
+}
+
+// redraw restores cells to how they were when the stroke started, then draws the line or rectangle from the anchor to p.
+func (e *Editor) redraw(cells [][]uint, p Point) {
+	for x := range e.before {
+		copy(cells[x], e.before[x])
+	}
+
+	if e.tool == Line {
+		DrawLine(cells, e.anchor, p, e.size, e.paint)
+	} else {
+		FillRectangle(cells, e.anchor, p, e.paint)
+	}
+}
+
+// set sets the cell at p to state, if it is on the grid.
+func set(cells [][]uint, p Point, state uint) {
+	if p.X < 0 || p.X >= len(cells) || p.Y < 0 || p.Y >= len(cells[p.X]) {
+		return
+	}
+	cells[p.X][p.Y] = state
+}
+
+// Stamp sets every cell under a square brush of the given size, centred on p, to state.
+// Even sizes have one more cell above and to the right of p than below and to the left.
+func Stamp(cells [][]uint, p Point, size, state uint) {
+	low, high := -int(max(size, 1)-1)/2, int(size)/2
+	for dx := low; dx <= high; dx++ {
+		for dy := low; dy <= high; dy++ {
+			set(cells, Point{X: p.X + dx, Y: p.Y + dy}, state)
+		}
+	}
+}
+
+// DrawLine stamps a square brush of the given size at every cell along the straight line from a to b, setting the cells under it to state.
+func DrawLine(cells [][]uint, a, b Point, size, state uint) {
+	// Bresenham's line algorithm, which steps one cell at a time along the longer axis
+	dx, dy := abs(b.X-a.X), -abs(b.Y-a.Y)
+	sx, sy := sign(b.X-a.X), sign(b.Y-a.Y)
+	err := dx + dy
+
+	p := a
+	for {
+		Stamp(cells, p, size, state)
+		if p == b {
+			return
+		}
+
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			p.X += sx
+		}
+		if e2 <= dx {
+			err += dx
+			p.Y += sy
+		}
+	}
+}
+
+// FillRectangle sets every cell in the rectangle with opposite corners a and b, inclusive, to state.
+func FillRectangle(cells [][]uint, a, b Point, state uint) {
+	for x := min(a.X, b.X); x <= max(a.X, b.X); x++ {
+		for y := min(a.Y, b.Y); y <= max(a.Y, b.Y); y++ {
+			set(cells, Point{X: x, Y: y}, state)
+		}
+	}
+}
+
+// FloodFill sets the cell at p, and every cell connected to it through cells sharing its state, to state.
+// Cells are connected to the four cells sharing an edge with them. It does nothing if p is not on the grid.
+func FloodFill(cells [][]uint, p Point, state uint) {
+	if p.X < 0 || p.X >= len(cells) || p.Y < 0 || p.Y >= len(cells[p.X]) {
+		return
+	}
+
+	target := cells[p.X][p.Y]
+	if target == state {
+		return
+	}
+
+	stack := []Point{p}
+	cells[p.X][p.Y] = state
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, n := range []Point{{X: p.X - 1, Y: p.Y}, {X: p.X + 1, Y: p.Y}, {X: p.X, Y: p.Y - 1}, {X: p.X, Y: p.Y + 1}} {
+			if n.X < 0 || n.X >= len(cells) || n.Y < 0 || n.Y >= len(cells[n.X]) || cells[n.X][n.Y] != target {
+				continue
+			}
+			cells[n.X][n.Y] = state
+			stack = append(stack, n)
+		}
+	}
+}
+
+func cloneCells(cells [][]uint) [][]uint {
+	clone := make([][]uint, len(cells))
+	for x := range cells {
+		clone[x] = make([]uint, len(cells[x]))
+		copy(clone[x], cells[x])
+	}
+	return clone
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
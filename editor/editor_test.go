@@ -0,0 +1,246 @@
+package editor
+
+import (
+	"reflect"
+	"testing"
+)
+
+// picture builds a grid from rows of digits, listed from top to bottom, so tests can draw the grid they expect.
+func picture(rows ...string) [][]uint {
+	cells := make([][]uint, len(rows[0]))
+	for x := range cells {
+		cells[x] = make([]uint, len(rows))
+		for y := range cells[x] {
+			cells[x][y] = uint(rows[len(rows)-1-y][x] - '0')
+		}
+	}
+	return cells
+}
+
+func TestStamp(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Point
+		size uint
+		want [][]uint
+	}{
+		{"single cell", Point{1, 1}, 1, picture("0000", "0000", "0100", "0000")},
+		{"odd size", Point{1, 1}, 3, picture("0000", "1110", "1110", "1110")},
+		{"even size", Point{1, 1}, 2, picture("0000", "0110", "0110", "0000")},
+		{"clipped", Point{0, 3}, 3, picture("1100", "1100", "0000", "0000")},
+		{"off the grid", Point{9, 9}, 3, picture("0000", "0000", "0000", "0000")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cells := picture("0000", "0000", "0000", "0000")
+			Stamp(cells, tt.p, tt.size, 1)
+			if !reflect.DeepEqual(cells, tt.want) {
+				t.Errorf("Stamp() = %v, want %v", cells, tt.want)
+			}
+		})
+	}
+}
+
+func TestDrawLine(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Point
+		want [][]uint
+	}{
+		{"horizontal", Point{0, 1}, Point{3, 1}, picture("0000", "0000", "1111", "0000")},
+		{"vertical", Point{2, 3}, Point{2, 0}, picture("0010", "0010", "0010", "0010")},
+		{"diagonal", Point{0, 0}, Point{3, 3}, picture("0001", "0010", "0100", "1000")},
+		{"shallow", Point{0, 0}, Point{3, 1}, picture("0000", "0000", "0011", "1100")},
+		{"point", Point{1, 2}, Point{1, 2}, picture("0000", "0100", "0000", "0000")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cells := picture("0000", "0000", "0000", "0000")
+			DrawLine(cells, tt.a, tt.b, 1, 1)
+			if !reflect.DeepEqual(cells, tt.want) {
+				t.Errorf("DrawLine() = %v, want %v", cells, tt.want)
+			}
+		})
+	}
+}
+
+func TestFillRectangle(t *testing.T) {
+	cells := picture("0000", "0000", "0000", "0000")
+	FillRectangle(cells, Point{2, 0}, Point{1, 2}, 3)
+	if want := picture("0000", "0330", "0330", "0330"); !reflect.DeepEqual(cells, want) {
+		t.Errorf("FillRectangle() = %v, want %v", cells, want)
+	}
+}
+
+func TestFloodFill(t *testing.T) {
+	tests := []struct {
+		name  string
+		p     Point
+		state uint
+		want  [][]uint
+	}{
+		{"inside", Point{1, 1}, 2, picture("11111", "12221", "12121", "12221", "11111")},
+		{"outside", Point{0, 0}, 2, picture("22222", "20002", "20102", "20002", "22222")},
+		{"isolated centre", Point{2, 2}, 2, picture("11111", "10001", "10201", "10001", "11111")},
+		{"same state", Point{0, 0}, 1, picture("11111", "10001", "10101", "10001", "11111")},
+		{"off the grid", Point{-1, 0}, 2, picture("11111", "10001", "10101", "10001", "11111")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cells := picture("11111", "10001", "10101", "10001", "11111")
+			FloodFill(cells, tt.p, tt.state)
+			if !reflect.DeepEqual(cells, tt.want) {
+				t.Errorf("FloodFill() = %v, want %v", cells, tt.want)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name       string
+		states     uint
+		background uint
+		wantState  uint
+		wantErr    bool
+	}{
+		{"two states", 2, 0, 1, false},
+		{"one state", 1, 0, 0, false},
+		{"no states", 0, 0, 0, true},
+		{"invalid background", 3, 3, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.states, tt.background)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && e.GetState() != tt.wantState {
+				t.Errorf("New().GetState() = %v, want %v", e.GetState(), tt.wantState)
+			}
+		})
+	}
+}
+
+func TestEditor_Set(t *testing.T) {
+	e, err := New(4, 0)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if err := e.SetState(3); err != nil || e.GetState() != 3 {
+		t.Errorf("SetState(3) error = %v, GetState() = %v", err, e.GetState())
+	}
+	if err := e.SetState(4); err == nil {
+		t.Errorf("SetState(4) should return an error")
+	}
+	if err := e.SetBrushSize(5); err != nil || e.GetBrushSize() != 5 {
+		t.Errorf("SetBrushSize(5) error = %v, GetBrushSize() = %v", err, e.GetBrushSize())
+	}
+	for _, size := range []uint{0, MaxBrushSize + 1} {
+		if err := e.SetBrushSize(size); err == nil {
+			t.Errorf("SetBrushSize(%v) should return an error", size)
+		}
+	}
+	if err := e.SetTool(Fill); err != nil || e.GetTool() != Fill {
+		t.Errorf("SetTool(Fill) error = %v, GetTool() = %v", err, e.GetTool())
+	}
+	if err := e.SetTool(toolCount); err == nil {
+		t.Errorf("SetTool(toolCount) should return an error")
+	}
+	if got := Fill.Next(); got != Brush {
+		t.Errorf("Fill.Next() = %v, want %v", got, Brush)
+	}
+}
+
+func TestEditor_Strokes(t *testing.T) {
+	tests := []struct {
+		name  string
+		tool  Tool
+		erase bool
+		start string
+		drag  []Point
+		want  [][]uint
+	}{
+		{
+			name: "brush",
+			tool: Brush,
+			drag: []Point{{3, 0}, {3, 3}},
+			want: picture("0001", "0001", "0001", "1111"),
+		},
+		{
+			name: "line previews",
+			tool: Line,
+			drag: []Point{{0, 3}, {3, 3}, {3, 0}},
+			want: picture("0000", "0000", "0000", "1111"),
+		},
+		{
+			name: "rectangle previews",
+			tool: Rectangle,
+			drag: []Point{{3, 3}, {1, 1}},
+			want: picture("0000", "0000", "1100", "1100"),
+		},
+		{
+			name:  "erase",
+			tool:  Brush,
+			erase: true,
+			start: "2222",
+			drag:  []Point{{0, 3}},
+			want:  picture("0222", "0222", "0222", "0222"),
+		},
+		{
+			name:  "fill",
+			tool:  Fill,
+			start: "0200",
+			drag:  []Point{{3, 3}},
+			want:  picture("1200", "1200", "1200", "1200"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(3, 0)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if err := e.SetTool(tt.tool); err != nil {
+				t.Fatalf("SetTool() error = %v", err)
+			}
+
+			row := tt.start
+			if row == "" {
+				row = "0000"
+			}
+			cells := picture(row, row, row, row)
+
+			e.Press(cells, Point{0, 0}, tt.erase)
+			if !e.Drawing() {
+				t.Errorf("Drawing() = false during a stroke")
+			}
+			for _, p := range tt.drag {
+				e.Drag(cells, p)
+			}
+			changes := e.Release()
+			if e.Drawing() {
+				t.Errorf("Drawing() = true after Release()")
+			}
+
+			if !reflect.DeepEqual(cells, tt.want) {
+				t.Errorf("stroke = %v, want %v", cells, tt.want)
+			}
+
+			// the changes returned should lead back from the result to where the stroke started
+			for _, change := range changes {
+				if cells[change.At.X][change.At.Y] != change.To {
+					t.Errorf("change %+v does not match the grid", change)
+				}
+				cells[change.At.X][change.At.Y] = change.From
+			}
+			if want := picture(row, row, row, row); !reflect.DeepEqual(cells, want) {
+				t.Errorf("reverting Release() changes = %v, want %v", cells, want)
+			}
+			if changes := e.Release(); changes != nil {
+				t.Errorf("Release() with no stroke in progress = %v, want nil", changes)
+			}
+		})
+	}
+}
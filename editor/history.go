@@ -0,0 +1,99 @@
+package editor
+
+// Change is a single cell changing state, at a point in a grid.
+type Change struct {
+	At       Point
+	From, To uint
+}
+
+// Diff returns the changes that turn before into after. Both grids must have the same dimensions.
+func Diff(before, after [][]uint) []Change {
+	var changes []Change
+	for x := range before {
+		for y, from := range before[x] {
+			if to := after[x][y]; to != from {
+				changes = append(changes, Change{At: Point{X: x, Y: y}, From: from, To: to})
+			}
+		}
+	}
+	return changes
+}
+
+// Edit is a set of changes made to one grid, along with how to set a cell of that grid, so the changes can be undone and redone later.
+type Edit struct {
+	Changes []Change
+	// Set sets the cell at p to state.
+	Set func(p Point, state uint)
+}
+
+// History is a stack of actions that can be undone and redone, where each action is one or more [Edit]s made together, such as a whole stroke.
+// You should use the [NewHistory] function to create one.
+type History struct {
+	undo, redo [][]Edit
+	limit      int
+}
+
+// NewHistory constructs an empty History, which forgets its oldest actions once it holds more than limit of them.
+func NewHistory(limit uint) *History {
+	return &History{limit: int(max(limit, 1))}
+}
+
+// Record adds an action made of edits to the history, and forgets any actions that had been undone.
+// Edits without any changes are dropped, and nothing is recorded if none are left.
+func (h *History) Record(edits ...Edit) {
+	var action []Edit
+	for _, edit := range edits {
+		if len(edit.Changes) > 0 {
+			action = append(action, edit)
+		}
+	}
+	if len(action) == 0 {
+		return
+	}
+
+	h.undo = append(h.undo, action)
+	if len(h.undo) > h.limit {
+		h.undo = h.undo[len(h.undo)-h.limit:]
+	}
+	h.redo = nil
+}
+
+// Undo reverts the most recent action, reporting false if there was nothing to undo.
+func (h *History) Undo() bool {
+	if len(h.undo) == 0 {
+		return false
+	}
+
+	action := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+
+	// changes are reverted in the opposite order to the one they were made in
+	for i := len(action) - 1; i >= 0; i-- {
+		edit := action[i]
+		for j := len(edit.Changes) - 1; j >= 0; j-- {
+			edit.Set(edit.Changes[j].At, edit.Changes[j].From)
+		}
+	}
+
+	h.redo = append(h.redo, action)
+	return true
+}
+
+// Redo makes the most recently undone action again, reporting false if there was nothing to redo.
+func (h *History) Redo() bool {
+	if len(h.redo) == 0 {
+		return false
+	}
+
+	action := h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+
+	for _, edit := range action {
+		for _, change := range edit.Changes {
+			edit.Set(change.At, change.To)
+		}
+	}
+
+	h.undo = append(h.undo, action)
+	return true
+}
@@ -0,0 +1,118 @@
+package editor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	before := picture("000", "120")
+	after := picture("300", "100")
+	want := []Change{{At: Point{0, 1}, From: 0, To: 3}, {At: Point{1, 0}, From: 2, To: 0}}
+
+	if got := Diff(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+	if got := Diff(before, before); got != nil {
+		t.Errorf("Diff() of identical grids = %v, want nil", got)
+	}
+}
+
+// stroke paints state over the given points of cells, and records them in h as a single action.
+func stroke(h *History, cells [][]uint, state uint, points ...Point) {
+	before := cloneCells(cells)
+	for _, p := range points {
+		cells[p.X][p.Y] = state
+	}
+	h.Record(Edit{Changes: Diff(before, cells), Set: func(p Point, state uint) { cells[p.X][p.Y] = state }})
+}
+
+func TestHistory(t *testing.T) {
+	cells := picture("000", "000")
+	h := NewHistory(10)
+
+	stroke(h, cells, 1, Point{0, 0}, Point{1, 0})
+	stroke(h, cells, 2, Point{1, 0}, Point{2, 1})
+	if want := picture("002", "120"); !reflect.DeepEqual(cells, want) {
+		t.Fatalf("cells = %v, want %v", cells, want)
+	}
+
+	if !h.Undo() {
+		t.Fatalf("Undo() = false, want true")
+	}
+	if want := picture("000", "110"); !reflect.DeepEqual(cells, want) {
+		t.Errorf("after Undo() cells = %v, want %v", cells, want)
+	}
+
+	if !h.Undo() {
+		t.Fatalf("Undo() = false, want true")
+	}
+	if want := picture("000", "000"); !reflect.DeepEqual(cells, want) {
+		t.Errorf("after second Undo() cells = %v, want %v", cells, want)
+	}
+	if h.Undo() {
+		t.Errorf("Undo() with nothing to undo = true, want false")
+	}
+
+	if !h.Redo() {
+		t.Fatalf("Redo() = false, want true")
+	}
+	if want := picture("000", "110"); !reflect.DeepEqual(cells, want) {
+		t.Errorf("after Redo() cells = %v, want %v", cells, want)
+	}
+
+	// a new action forgets what was undone
+	stroke(h, cells, 1, Point{2, 1})
+	if h.Redo() {
+		t.Errorf("Redo() after recording = true, want false")
+	}
+
+	// actions without changes are not recorded
+	stroke(h, cells, 1, Point{2, 1})
+	h.Undo()
+	if want := picture("000", "110"); !reflect.DeepEqual(cells, want) {
+		t.Errorf("after undoing an empty action cells = %v, want %v", cells, want)
+	}
+}
+
+func TestHistory_Edits(t *testing.T) {
+	// one action spanning two grids, e.g. two planes of a three-dimensional grid
+	first, second := picture("00"), picture("00")
+	h := NewHistory(10)
+
+	h.Record(
+		Edit{Changes: []Change{{At: Point{0, 0}, From: 0, To: 1}}, Set: func(p Point, state uint) { first[p.X][p.Y] = state }},
+		Edit{Changes: []Change{{At: Point{1, 0}, From: 0, To: 2}}, Set: func(p Point, state uint) { second[p.X][p.Y] = state }},
+	)
+	if !h.Undo() || !h.Redo() {
+		t.Fatalf("Undo() and Redo() should both succeed")
+	}
+	if !reflect.DeepEqual(first, picture("10")) || !reflect.DeepEqual(second, picture("02")) {
+		t.Errorf("after Redo() grids = %v, %v, want [[1] [0]], [[0] [2]]", first, second)
+	}
+
+	h.Undo()
+	if !reflect.DeepEqual(first, picture("00")) || !reflect.DeepEqual(second, picture("00")) {
+		t.Errorf("after Undo() grids = %v, %v, want both empty", first, second)
+	}
+}
+
+func TestHistory_Limit(t *testing.T) {
+	cells := picture("0000")
+	h := NewHistory(2)
+
+	for x := range 4 {
+		stroke(h, cells, 1, Point{x, 0})
+	}
+
+	undone := 0
+	for h.Undo() {
+		undone++
+	}
+	if undone != 2 {
+		t.Errorf("undid %v actions, want 2", undone)
+	}
+	if want := picture("1100"); !reflect.DeepEqual(cells, want) {
+		t.Errorf("cells = %v, want %v", cells, want)
+	}
+}
@@ -0,0 +1,49 @@
+package examples
+
+import (
+	"fmt"
+
+	"github.com/michael-ryan/cellularautomata/v2/model"
+)
+
+// NewBoscos returns a [model.Automaton] that represents Bosco's Rule, a Larger than Life rule over a radius 5 Moore neighbourhood.
+//
+// Each cell counts the alive cells in the 11x11 square around it, including itself.
+//   - A dead cell with 34 to 45 alive cells around it becomes alive.
+//   - An alive cell with 34 to 58 alive cells around it (itself included) survives, otherwise it dies.
+//
+// [https://conwaylife.com/wiki/Bosco%27s_Rule]
+func NewBoscos() *model.Automaton {
+	const (
+		dead = iota
+		alive
+	)
+
+	// built once, rather than in every predicate call
+	neighbourhood := append(model.MooreNeighbourhood(5), model.Offset{X: 0, Y: 0})
+
+	transitionSet := model.NewTransitionSet()
+
+	transitionSet.AddTransition(dead, alive, func(cell model.Cell) bool {
+		n := cell.Count(alive, neighbourhood)
+		return n >= 34 && n <= 45
+	})
+
+	transitionSet.AddTransition(alive, dead, func(cell model.Cell) bool {
+		n := cell.Count(alive, neighbourhood)
+		return n < 34 || n > 58
+	})
+
+	colouring := make([]model.Rgb, 2)
+	colouring[dead] = model.Rgb{R: 0, G: 0, B: 0}
+	colouring[alive] = model.Rgb{R: 1, G: 1, B: 1}
+
+	automaton, err := model.NewAutomaton(transitionSet, colouring)
+	if err != nil {
+		panic(fmt.Errorf("unrecoverable error, something went wrong constructing Bosco's Rule automaton: %w", err))
+	}
+
+	automaton.SetLocality(5)
+
+	return automaton
+}
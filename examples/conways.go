@@ -42,5 +42,7 @@ func NewConways() *model.Automaton {
 		panic(fmt.Errorf("unrecoverable error, something went wrong constructing Conway's Game of Life automaton: %w", err))
 	}
 
+	automaton.SetLocality(1)
+
 	return automaton
 }
@@ -2,7 +2,6 @@ package examples
 
 import (
 	"fmt"
-	"math/rand"
 
 	"github.com/michael-ryan/cellularautomata/v2/model"
 )
@@ -13,6 +12,8 @@ import (
 //   - If a dead cell has a neighbouring alive cell, it may turn into an alive cell. The more neighbouring alive cells, the higher the chance. There is also a very very low chance of a dead -> alive transition with no alive neighbours.
 //   - An alive cell may randomly catch fire with a very low chance. It also has a high chance of spreading fire from a neighbouring cell.
 //   - A cell that is on fire will (eventually) transition to the dead state, with a 70% chance rolled on each time step.
+//
+// All randomness comes from [model.Cell.Rand], so runs can be reproduced with [model.Automaton.SetSeed].
 func NewForest() *model.Automaton {
 	const (
 		dead = iota
@@ -25,12 +26,12 @@ func NewForest() *model.Automaton {
 	// dead rules
 	transitionSet.AddTransition(dead, alive, func(cell model.Cell) bool {
 		// grow a random tree
-		return rand.Float64() > 0.99999
+		return cell.Rand().Float64() > 0.99999
 	})
 	transitionSet.AddTransition(dead, alive, func(cell model.Cell) bool {
 		// grow a tree from a neighbouring tree
 		for range cell.CountNeighbours(alive, true) {
-			if rand.Float64() > 0.99 {
+			if cell.Rand().Float64() > 0.99 {
 				return true
 			}
 		}
@@ -41,17 +42,17 @@ func NewForest() *model.Automaton {
 	// alive rules
 	transitionSet.AddTransition(alive, onFire, func(cell model.Cell) bool {
 		// lightning sets a tree on fire
-		return rand.Float64() > 0.9999
+		return cell.Rand().Float64() > 0.9999
 	})
 	transitionSet.AddTransition(alive, onFire, func(cell model.Cell) bool {
 		// neighbouring tree on fire, catch fire
-		return cell.CountNeighbours(onFire, true) > 0 && rand.Float64() > 0.25
+		return cell.CountNeighbours(onFire, true) > 0 && cell.Rand().Float64() > 0.25
 	})
 
 	// burning rules
 	transitionSet.AddTransition(onFire, dead, func(cell model.Cell) bool {
 		// burn out
-		return rand.Float64() > 0.3
+		return cell.Rand().Float64() > 0.3
 	})
 
 	colouring := make([]model.Rgb, 3)
@@ -64,5 +65,8 @@ func NewForest() *model.Automaton {
 		panic(fmt.Errorf("unrecoverable error, something went wrong constructing Conway's Game of Life automaton: %w", err))
 	}
 
+	// lightning strikes at random, so every cell is still evaluated on every step
+	automaton.SetLocality(1)
+
 	return automaton
 }
@@ -47,5 +47,7 @@ func NewRainbow() *model.Automaton {
 		panic(fmt.Errorf("unrecoverable error, something went wrong constructing Rainbow automaton: %w", err))
 	}
 
+	automaton.SetLocality(1)
+
 	return automaton
 }
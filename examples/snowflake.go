@@ -0,0 +1,43 @@
+package examples
+
+import (
+	"fmt"
+
+	"github.com/michael-ryan/cellularautomata/v2/model"
+)
+
+// NewSnowflake returns a [model.Automaton] that represents Packard's snowflake, a growth model on a hexagonal grid.
+//
+// A dead cell with exactly one alive hexagonal neighbour freezes and becomes alive. Alive cells stay alive forever.
+// Starting from a single alive cell, the frozen cells grow into a six-fold symmetric snowflake.
+//
+// [https://www.wolframscience.com/nks/p371--the-growth-of-crystals/]
+func NewSnowflake() *model.Automaton {
+	const (
+		dead = iota
+		alive
+	)
+
+	transitionSet := model.NewTransitionSet()
+
+	transitionSet.AddTransition(dead, alive, func(cell model.Cell) bool {
+		return cell.CountHexNeighbours(alive) == 1
+	})
+
+	colouring := make([]model.Rgb, 2)
+	colouring[dead] = model.Rgb{R: 0, G: 0, B: 0.2}
+	colouring[alive] = model.Rgb{R: 0.8, G: 0.9, B: 1}
+
+	automaton, err := model.NewAutomaton(transitionSet, colouring)
+	if err != nil {
+		panic(fmt.Errorf("unrecoverable error, something went wrong constructing snowflake automaton: %w", err))
+	}
+
+	if err := automaton.SetTopology(model.Hexagonal); err != nil {
+		panic(fmt.Errorf("unrecoverable error, something went wrong setting snowflake topology: %w", err))
+	}
+
+	automaton.SetLocality(1)
+
+	return automaton
+}
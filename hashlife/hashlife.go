@@ -0,0 +1,378 @@
+// Package hashlife simulates two-state Life-like automata on an unbounded grid with Gosper's Hashlife algorithm.
+//
+// Hashlife stores the grid as a quadtree, shares identical blocks of cells, and memoises how each block evolves.
+// On patterns with a lot of repetition in space and time, it can advance billions of generations in the time [model.Automaton.Step] takes to advance a few,
+// and it can skip ahead 2^k generations in a single step. On chaotic patterns, it can be slower and use much more memory than stepping one generation at a time.
+// The memory used is bounded by [Universe.SetNodeLimit], at the cost of recomputing results that are forgotten.
+//
+// Cells are addressed by x and y coordinates, with positive x going right and positive y going up, as in the rest of this module.
+// Coordinates must stay between -2^60 and 2^60, including any that alive cells reach as the pattern grows.
+// Regions can be copied to and from the [][]uint grids used by the rest of this module, with states other than 0 counting as alive,
+// so that they can be drawn in the GUI or saved with the rle package.
+//
+// [https://conwaylife.com/wiki/HashLife]
+package hashlife
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxLevel is the largest root node a universe can have, so that every coordinate fits in an int64.
+const maxLevel = 62
+
+// defaultNodeLimit is the number of nodes a universe may hold before it collects those it no longer needs, hundreds of megabytes' worth.
+const defaultNodeLimit = 1 << 21
+
+// Rule is an outer totalistic Life-like rule: whether a cell is alive in the next generation depends only on its state and the number of its eight neighbours that are alive.
+type Rule struct {
+	// Birth[n] reports whether a dead cell with n alive neighbours becomes alive.
+	Birth [9]bool
+	// Survival[n] reports whether an alive cell with n alive neighbours stays alive.
+	Survival [9]bool
+}
+
+// Conway returns the rule of Conway's Game of Life, B3/S23.
+func Conway() Rule {
+	return Rule{
+		Birth:    [9]bool{3: true},
+		Survival: [9]bool{2: true, 3: true},
+	}
+}
+
+// NewRule constructs a Rule from lists of the neighbour counts that cause a birth and allow survival.
+func NewRule(birth, survival []uint) (Rule, error) {
+	r := Rule{}
+	for _, n := range birth {
+		if n > 8 {
+			return r, fmt.Errorf("birth count %v invalid, a cell has at most 8 neighbours", n)
+		}
+		r.Birth[n] = true
+	}
+	for _, n := range survival {
+		if n > 8 {
+			return r, fmt.Errorf("survival count %v invalid, a cell has at most 8 neighbours", n)
+		}
+		r.Survival[n] = true
+	}
+
+	if r.Birth[0] {
+		return r, fmt.Errorf("rules with birth on 0 neighbours are not supported, as the empty grid would not stay empty")
+	}
+
+	return r, nil
+}
+
+// ParseRule constructs a Rule from a rule string in B/S notation, such as "B3/S23" or "B36/S23".
+// The conditions may be given in either order, and the slash may be omitted, as in "B3S23".
+//
+// Hensel notation, as accepted by [model.ParseLifeRule], is not supported.
+func ParseRule(rule string) (Rule, error) {
+	upper := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(rule), "/", ""))
+
+	var birth, survival []uint
+	var list *[]uint
+	for _, c := range upper {
+		switch {
+		case c == 'B':
+			list = &birth
+		case c == 'S':
+			list = &survival
+		case c >= '0' && c <= '8' && list != nil:
+			*list = append(*list, uint(c-'0'))
+		default:
+			return Rule{}, fmt.Errorf("rule %q must be of the form B3/S23", rule)
+		}
+	}
+
+	if !strings.Contains(upper, "B") || !strings.Contains(upper, "S") {
+		return Rule{}, fmt.Errorf("rule %q must give both birth (B) and survival (S) conditions", rule)
+	}
+
+	return NewRule(birth, survival)
+}
+
+// Universe is an unbounded grid of cells following a [Rule]. You should use the [New] function to create one.
+//
+// A Universe is not safe for concurrent use.
+type Universe struct {
+	rule        Rule
+	root        *node
+	generation  uint64
+	nodes       map[quadrants]*node
+	empties     []*node
+	alive, dead *node
+	nodeLimit   uint
+}
+
+// New constructs a Universe following rule, with every cell dead, at generation 0.
+func New(rule Rule) *Universe {
+	u := &Universe{
+		rule:      rule,
+		nodes:     make(map[quadrants]*node),
+		alive:     &node{population: 1},
+		dead:      &node{},
+		nodeLimit: defaultNodeLimit,
+	}
+	u.empties = []*node{u.dead}
+	u.root = u.empty(3)
+	return u
+}
+
+// SetNodeLimit sets the number of distinct blocks of cells the universe may hold before it collects those it no longer needs.
+// Once a step leaves more than n, every block that is not part of the current pattern is forgotten, along with every memoised result,
+// so later steps must recompute them. If n is 0, nothing is ever collected. The default is about 2 million blocks.
+func (u *Universe) SetNodeLimit(n uint) {
+	u.nodeLimit = n
+}
+
+// Rule returns the rule this universe follows.
+func (u *Universe) Rule() Rule {
+	return u.rule
+}
+
+// Generation returns the number of generations simulated so far.
+func (u *Universe) Generation() uint64 {
+	return u.generation
+}
+
+// Population returns the number of alive cells.
+func (u *Universe) Population() uint64 {
+	return u.root.population
+}
+
+// half returns half the width of the root node, so the root covers coordinates from -half to half - 1 on both axes.
+func (u *Universe) half() int64 {
+	return int64(1) << (u.root.level - 1)
+}
+
+// contains reports whether (x, y) is covered by the root node.
+func (u *Universe) contains(x, y int64) bool {
+	half := u.half()
+	return x >= -half && x < half && y >= -half && y < half
+}
+
+// expand doubles the width of the root node, keeping the existing cells at the centre.
+func (u *Universe) expand() {
+	r := u.root
+	if r.level >= maxLevel {
+		panic("universe too large, coordinates must stay between -2^60 and 2^60")
+	}
+
+	e := u.empty(r.level - 1)
+	u.root = u.join(
+		u.join(e, e, e, r.nw),
+		u.join(e, e, r.ne, e),
+		u.join(e, r.sw, e, e),
+		u.join(r.se, e, e, e),
+	)
+}
+
+// Set sets the cell at (x, y) to be alive or dead.
+func (u *Universe) Set(x, y int64, alive bool) {
+	for !u.contains(x, y) {
+		u.expand()
+	}
+
+	leaf := u.dead
+	if alive {
+		leaf = u.alive
+	}
+	u.root = u.set(u.root, x, y, leaf)
+}
+
+// set returns n with the cell at (x, y), relative to the centre of n, replaced by leaf.
+func (u *Universe) set(n *node, x, y int64, leaf *node) *node {
+	nw, ne, sw, se := n.nw, n.ne, n.sw, n.se
+
+	if n.level == 1 {
+		switch {
+		case x < 0 && y >= 0:
+			nw = leaf
+		case x >= 0 && y >= 0:
+			ne = leaf
+		case x < 0:
+			sw = leaf
+		default:
+			se = leaf
+		}
+		return u.join(nw, ne, sw, se)
+	}
+
+	// move the origin to the centre of the quadrant holding (x, y)
+	quarter := int64(1) << (n.level - 2)
+	switch {
+	case x < 0 && y >= 0:
+		nw = u.set(nw, x+quarter, y-quarter, leaf)
+	case x >= 0 && y >= 0:
+		ne = u.set(ne, x-quarter, y-quarter, leaf)
+	case x < 0:
+		sw = u.set(sw, x+quarter, y+quarter, leaf)
+	default:
+		se = u.set(se, x-quarter, y+quarter, leaf)
+	}
+	return u.join(nw, ne, sw, se)
+}
+
+// Get reports whether the cell at (x, y) is alive.
+func (u *Universe) Get(x, y int64) bool {
+	if !u.contains(x, y) {
+		return false
+	}
+
+	n := u.root
+	for n.level > 1 {
+		if n.population == 0 {
+			return false
+		}
+
+		quarter := int64(1) << (n.level - 2)
+		switch {
+		case x < 0 && y >= 0:
+			n, x, y = n.nw, x+quarter, y-quarter
+		case x >= 0 && y >= 0:
+			n, x, y = n.ne, x-quarter, y-quarter
+		case x < 0:
+			n, x, y = n.sw, x+quarter, y+quarter
+		default:
+			n, x, y = n.se, x-quarter, y+quarter
+		}
+	}
+
+	switch {
+	case x < 0 && y >= 0:
+		return n.nw == u.alive
+	case x >= 0 && y >= 0:
+		return n.ne == u.alive
+	case x < 0:
+		return n.sw == u.alive
+	default:
+		return n.se == u.alive
+	}
+}
+
+// SetRegion copies cells, indexed as cells[x][y], into the universe with cells[0][0] at (x, y). States other than 0 are alive.
+// Every cell of the region is overwritten, including dead ones.
+func (u *Universe) SetRegion(cells [][]uint, x, y int64) {
+	for i := range cells {
+		for j, state := range cells[i] {
+			u.Set(x+int64(i), y+int64(j), state != 0)
+		}
+	}
+}
+
+// Region copies the width by height block of cells with its bottom left corner at (x, y) into a new grid, indexed as cells[x][y].
+// Alive cells are state 1 and dead cells are state 0.
+func (u *Universe) Region(x, y int64, width, height uint) [][]uint {
+	cells := make([][]uint, width)
+	for i := range cells {
+		cells[i] = make([]uint, height)
+	}
+
+	half := u.half()
+	u.region(u.root, -half, -half, cells, x, y)
+	return cells
+}
+
+// region writes the alive cells of n, whose bottom left corner is at (left, bottom), into cells, whose bottom left corner is at (x, y).
+func (u *Universe) region(n *node, left, bottom int64, cells [][]uint, x, y int64) {
+	if n.population == 0 {
+		return
+	}
+
+	size := int64(1) << n.level
+	width, height := int64(len(cells)), int64(0)
+	if width > 0 {
+		height = int64(len(cells[0]))
+	}
+	if left >= x+width || left+size <= x || bottom >= y+height || bottom+size <= y {
+		return
+	}
+
+	if n.level == 0 {
+		cells[left-x][bottom-y] = 1
+		return
+	}
+
+	half := size / 2
+	u.region(n.nw, left, bottom+half, cells, x, y)
+	u.region(n.ne, left+half, bottom+half, cells, x, y)
+	u.region(n.sw, left, bottom, cells, x, y)
+	u.region(n.se, left+half, bottom, cells, x, y)
+}
+
+// Bounds returns the smallest rectangle containing every alive cell, as the coordinates of its bottom left corner and its size.
+// ok is false if there are no alive cells.
+func (u *Universe) Bounds() (x, y int64, width, height uint, ok bool) {
+	if u.root.population == 0 {
+		return 0, 0, 0, 0, false
+	}
+
+	half := u.half()
+	minX, minY := half, half
+	maxX, maxY := -half-1, -half-1
+	u.bounds(u.root, -half, -half, &minX, &minY, &maxX, &maxY)
+
+	return minX, minY, uint(maxX - minX + 1), uint(maxY - minY + 1), true
+}
+
+// bounds widens the rectangle from (minX, minY) to (maxX, maxY) to include every alive cell of n, whose bottom left corner is at (left, bottom).
+func (u *Universe) bounds(n *node, left, bottom int64, minX, minY, maxX, maxY *int64) {
+	if n.population == 0 {
+		return
+	}
+
+	size := int64(1) << n.level
+	// skip nodes that could not widen the rectangle
+	if left >= *minX && left+size-1 <= *maxX && bottom >= *minY && bottom+size-1 <= *maxY {
+		return
+	}
+
+	if n.level == 0 {
+		*minX, *maxX = min(*minX, left), max(*maxX, left)
+		*minY, *maxY = min(*minY, bottom), max(*maxY, bottom)
+		return
+	}
+
+	half := size / 2
+	u.bounds(n.nw, left, bottom+half, minX, minY, maxX, maxY)
+	u.bounds(n.ne, left+half, bottom+half, minX, minY, maxX, maxY)
+	u.bounds(n.sw, left, bottom, minX, minY, maxX, maxY)
+	u.bounds(n.se, left+half, bottom, minX, minY, maxX, maxY)
+}
+
+// Step advances the universe by 2^k generations at once. k must be at most 59.
+func (u *Universe) Step(k uint) {
+	if k+3 > maxLevel {
+		panic(fmt.Sprintf("cannot step 2^%v generations at once, k must be at most %v", k, maxLevel-3))
+	}
+
+	// the root must be large enough to advance 2^k generations, and every alive cell must be far enough from its edges that nothing escapes
+	for u.root.level < k+3 || !u.padded() {
+		u.expand()
+	}
+
+	u.root = u.evolve(u.root, k)
+	u.generation += 1 << k
+
+	if u.nodeLimit != 0 && uint(len(u.nodes)) > u.nodeLimit {
+		u.collect()
+	}
+}
+
+// padded reports whether every alive cell is within the square at the centre of the root node that is a quarter of its width.
+func (u *Universe) padded() bool {
+	r := u.root
+	inner := r.nw.se.se.population + r.ne.sw.sw.population + r.sw.ne.ne.population + r.se.nw.nw.population
+	return inner == r.population
+}
+
+// Advance advances the universe by n generations, in as few steps of [Universe.Step] as possible.
+func (u *Universe) Advance(n uint64) {
+	for k := uint(0); n != 0; k++ {
+		if n&1 != 0 {
+			u.Step(k)
+		}
+		n >>= 1
+	}
+}
@@ -0,0 +1,202 @@
+package hashlife
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/michael-ryan/cellularautomata/v2/model"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		want    Rule
+		wantErr bool
+	}{
+		{name: "conway", rule: "B3/S23", want: Conway()},
+		{name: "no slash", rule: "b3s23", want: Conway()},
+		{name: "survival first", rule: "S23/B3", want: Conway()},
+		{name: "highlife", rule: "B36/S23", want: Rule{Birth: [9]bool{3: true, 6: true}, Survival: [9]bool{2: true, 3: true}}},
+		{name: "empty survival", rule: "B2/S", want: Rule{Birth: [9]bool{2: true}}},
+		{name: "missing survival", rule: "B3", wantErr: true},
+		{name: "nine neighbours", rule: "B39/S23", wantErr: true},
+		{name: "birth on zero", rule: "B03/S23", wantErr: true},
+		{name: "hensel", rule: "B2a/S", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRule(tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseRule() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("ParseRule() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUniverse_SetGet(t *testing.T) {
+	u := New(Conway())
+
+	points := [][2]int64{{0, 0}, {-1, -1}, {-1, 0}, {5, -7}, {-1000, 123456}}
+	for _, p := range points {
+		u.Set(p[0], p[1], true)
+	}
+
+	for _, p := range points {
+		if !u.Get(p[0], p[1]) {
+			t.Errorf("Universe.Get(%v, %v) = false, want true", p[0], p[1])
+		}
+	}
+	if u.Get(1, 0) || u.Get(0, -1) || u.Get(1<<40, 0) {
+		t.Errorf("Universe.Get() reported a dead cell as alive")
+	}
+	if got := u.Population(); got != uint64(len(points)) {
+		t.Errorf("Universe.Population() = %v, want %v", got, len(points))
+	}
+
+	u.Set(5, -7, false)
+	if u.Get(5, -7) || u.Population() != uint64(len(points)-1) {
+		t.Errorf("Universe.Set() did not kill the cell")
+	}
+}
+
+func TestUniverse_Region(t *testing.T) {
+	u := New(Conway())
+
+	cells := [][]uint{{1, 0, 2}, {0, 0, 0}, {0, 1, 1}}
+	u.SetRegion(cells, -2, 3)
+
+	want := [][]uint{{1, 0, 1}, {0, 0, 0}, {0, 1, 1}}
+	if got := u.Region(-2, 3, 3, 3); !reflect.DeepEqual(got, want) {
+		t.Errorf("Universe.Region() = %v, want %v", got, want)
+	}
+
+	// a region partly outside the pattern
+	want = [][]uint{{0, 0}, {0, 1}}
+	if got := u.Region(-3, 4, 2, 2); !reflect.DeepEqual(got, want) {
+		t.Errorf("Universe.Region() = %v, want %v", got, want)
+	}
+
+	x, y, width, height, ok := u.Bounds()
+	if !ok || x != -2 || y != 3 || width != 3 || height != 3 {
+		t.Errorf("Universe.Bounds() = %v, %v, %v, %v, %v, want -2, 3, 3, 3, true", x, y, width, height, ok)
+	}
+
+	if _, _, _, _, ok := New(Conway()).Bounds(); ok {
+		t.Errorf("Universe.Bounds() of an empty universe should not be ok")
+	}
+}
+
+// TestUniverse_Advance checks that Hashlife agrees with brute force stepping of the same rule.
+func TestUniverse_Advance(t *testing.T) {
+	tests := []struct {
+		name        string
+		rule        string
+		generations uint64
+	}{
+		{name: "conway", rule: "B3/S23", generations: 37},
+		{name: "highlife", rule: "B36/S23", generations: 20},
+		{name: "seeds", rule: "B2/S", generations: 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRule(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseRule() error = %v", err)
+			}
+			a, err := model.ParseLifeRule(tt.rule)
+			if err != nil {
+				t.Fatalf("model.ParseLifeRule() error = %v", err)
+			}
+
+			// the soup sits in the middle of a grid large enough that nothing reaches the edge
+			const size, soup = 160, 16
+			r := rand.New(rand.NewSource(1))
+			cells := model.NewGrid(size, size, 0)
+			for x := range soup {
+				for y := range soup {
+					cells[(size-soup)/2+x][(size-soup)/2+y] = uint(r.Intn(2))
+				}
+			}
+
+			u := New(rule)
+			u.SetRegion(cells, -size/2, -size/2)
+			u.Advance(tt.generations)
+
+			next := model.NewGrid(size, size, 0)
+			for generation := range tt.generations {
+				a.StepWith(cells, next, model.StepOptions{Generation: uint(generation)})
+				cells, next = next, cells
+			}
+
+			if got := u.Region(-size/2, -size/2, size, size); !reflect.DeepEqual(got, cells) {
+				t.Errorf("Universe.Advance(%v) disagrees with model.Automaton.Step", tt.generations)
+			}
+			if got := u.Generation(); got != tt.generations {
+				t.Errorf("Universe.Generation() = %v, want %v", got, tt.generations)
+			}
+		})
+	}
+}
+
+func TestUniverse_Step_Glider(t *testing.T) {
+	u := New(Conway())
+
+	// a glider travelling up and to the left, one cell diagonally every 4 generations
+	glider := [][]uint{{0, 1, 1}, {1, 0, 1}, {0, 0, 1}}
+	u.SetRegion(glider, 0, 0)
+
+	const k = 40
+	u.Step(k)
+
+	if got := u.Population(); got != 5 {
+		t.Fatalf("Universe.Population() = %v, want 5", got)
+	}
+
+	const moved = 1 << (k - 2)
+	if got := u.Region(-moved, moved, 3, 3); !reflect.DeepEqual(got, glider) {
+		t.Errorf("glider after 2^%v generations = %v, want %v", k, got, glider)
+	}
+}
+
+func TestUniverse_SetNodeLimit(t *testing.T) {
+	const limit, size = 2000, 32
+	r := rand.New(rand.NewSource(2))
+	soup := model.NewGrid(size, size, 0)
+	for x := range soup {
+		for y := range soup[x] {
+			soup[x][y] = uint(r.Intn(2))
+		}
+	}
+
+	limited, unlimited := New(Conway()), New(Conway())
+	limited.SetNodeLimit(limit)
+	unlimited.SetNodeLimit(0)
+	limited.SetRegion(soup, 0, 0)
+	unlimited.SetRegion(soup, 0, 0)
+
+	collected := false
+	for k := range uint(8) {
+		limited.Step(k)
+		unlimited.Step(k)
+
+		if got := len(limited.nodes); got > limit {
+			t.Fatalf("Universe holds %v nodes after a step, want at most %v", got, limit)
+		}
+		collected = collected || len(limited.nodes) < len(unlimited.nodes)
+
+		x, y, width, height, _ := unlimited.Bounds()
+		if !reflect.DeepEqual(limited.Region(x, y, width, height), unlimited.Region(x, y, width, height)) {
+			t.Fatalf("generation %v: Universe with a node limit differs from one without", unlimited.Generation())
+		}
+	}
+
+	if !collected {
+		t.Errorf("Universe with a node limit of %v never collected any nodes", limit)
+	}
+}
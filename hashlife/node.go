@@ -0,0 +1,168 @@
+package hashlife
+
+// node is a square block of cells, 2^level cells wide, stored as a quadtree.
+// Nodes are immutable and hash-consed by their [Universe], so identical blocks are always the same node and can share memoised results.
+type node struct {
+	// nw, ne, sw and se are the four quadrants of this node, each one level lower. They are nil for leaves.
+	nw, ne, sw, se *node
+	level          uint
+	population     uint64
+	// results[j] is the centre of this node, one level lower, 2^j generations into the future
+	results map[uint]*node
+}
+
+// quadrants identifies a node by its children, for hash-consing.
+type quadrants [4]*node
+
+// centre returns the node one level lower at the centre of n. n must be at least level 2.
+func (u *Universe) centre(n *node) *node {
+	return u.join(n.nw.se, n.ne.sw, n.sw.ne, n.se.nw)
+}
+
+// join returns the node with the given quadrants, creating it if it does not exist yet.
+func (u *Universe) join(nw, ne, sw, se *node) *node {
+	key := quadrants{nw, ne, sw, se}
+	if n, ok := u.nodes[key]; ok {
+		return n
+	}
+
+	n := &node{
+		nw:         nw,
+		ne:         ne,
+		sw:         sw,
+		se:         se,
+		level:      nw.level + 1,
+		population: nw.population + ne.population + sw.population + se.population,
+	}
+	u.nodes[key] = n
+	return n
+}
+
+// collect rebuilds the hash-consing table from only the nodes that make up the root and the empty nodes, and forgets every memoised result.
+// Every other node, including those the results referred to, can then be reclaimed by the garbage collector.
+func (u *Universe) collect() {
+	u.nodes = make(map[quadrants]*node)
+
+	var keep func(n *node)
+	keep = func(n *node) {
+		if n.level == 0 {
+			return
+		}
+		key := quadrants{n.nw, n.ne, n.sw, n.se}
+		if _, ok := u.nodes[key]; ok {
+			return
+		}
+
+		n.results = nil
+		u.nodes[key] = n
+		keep(n.nw)
+		keep(n.ne)
+		keep(n.sw)
+		keep(n.se)
+	}
+
+	keep(u.root)
+	for _, e := range u.empties {
+		keep(e)
+	}
+}
+
+// empty returns the node of the given level with no alive cells.
+func (u *Universe) empty(level uint) *node {
+	for uint(len(u.empties)) <= level {
+		lower := u.empties[len(u.empties)-1]
+		u.empties = append(u.empties, u.join(lower, lower, lower, lower))
+	}
+	return u.empties[level]
+}
+
+// evolve returns the centre of n, one level lower, 2^j generations into the future. n must be at least level 2, and j at most n.level - 2.
+func (u *Universe) evolve(n *node, j uint) *node {
+	if n.population == 0 {
+		return u.empty(n.level - 1)
+	}
+
+	if result, ok := n.results[j]; ok {
+		return result
+	}
+
+	var result *node
+	if n.level == 2 {
+		result = u.evolveLeaf(n)
+	} else {
+		result = u.evolveInner(n, j)
+	}
+
+	if n.results == nil {
+		n.results = make(map[uint]*node)
+	}
+	n.results[j] = result
+	return result
+}
+
+// evolveInner evolves a node of at least level 3, by splitting it into nine overlapping nodes one level lower, as in Gosper's algorithm.
+func (u *Universe) evolveInner(n *node, j uint) *node {
+	// the nine overlapping nodes, named by row and column from the top left
+	n00 := n.nw
+	n01 := u.join(n.nw.ne, n.ne.nw, n.nw.se, n.ne.sw)
+	n02 := n.ne
+	n10 := u.join(n.nw.sw, n.nw.se, n.sw.nw, n.sw.ne)
+	n11 := u.centre(n)
+	n12 := u.join(n.ne.sw, n.ne.se, n.se.nw, n.se.ne)
+	n20 := n.sw
+	n21 := u.join(n.sw.ne, n.se.nw, n.sw.se, n.se.sw)
+	n22 := n.se
+
+	// at full speed, both halves of the journey advance 2^(j-1) generations. Otherwise, the first half just takes the centre
+	first := u.centre
+	if j == n.level-2 {
+		first = func(m *node) *node { return u.evolve(m, j-1) }
+	}
+	second := j
+	if j == n.level-2 {
+		second = j - 1
+	}
+
+	a00, a01, a02 := first(n00), first(n01), first(n02)
+	a10, a11, a12 := first(n10), first(n11), first(n12)
+	a20, a21, a22 := first(n20), first(n21), first(n22)
+
+	return u.join(
+		u.evolve(u.join(a00, a01, a10, a11), second),
+		u.evolve(u.join(a01, a02, a11, a12), second),
+		u.evolve(u.join(a10, a11, a20, a21), second),
+		u.evolve(u.join(a11, a12, a21, a22), second),
+	)
+}
+
+// evolveLeaf evolves a level 2 node, 4 cells wide, by a single generation, by applying the rule to each of its four central cells.
+func (u *Universe) evolveLeaf(n *node) *node {
+	// cells[row][col], with row 0 at the top
+	var cells [4][4]bool
+	for row, pair := range [2][2]*node{{n.nw, n.ne}, {n.sw, n.se}} {
+		for col, q := range pair {
+			cells[2*row][2*col] = q.nw == u.alive
+			cells[2*row][2*col+1] = q.ne == u.alive
+			cells[2*row+1][2*col] = q.sw == u.alive
+			cells[2*row+1][2*col+1] = q.se == u.alive
+		}
+	}
+
+	next := func(row, col int) *node {
+		count := 0
+		for dr := -1; dr <= 1; dr++ {
+			for dc := -1; dc <= 1; dc++ {
+				if (dr != 0 || dc != 0) && cells[row+dr][col+dc] {
+					count++
+				}
+			}
+		}
+
+		if (cells[row][col] && u.rule.Survival[count]) || (!cells[row][col] && u.rule.Birth[count]) {
+			return u.alive
+		}
+		return u.dead
+	}
+
+	return u.join(next(1, 1), next(1, 2), next(2, 1), next(2, 2))
+}
@@ -0,0 +1,116 @@
+package model
+
+// activityTile is the width and height, in cells, of the square tiles an [Activity] tracks changes in.
+const activityTile = 16
+
+// Activity tracks which parts of a grid changed in a step, so that the next step can skip the parts that cannot change.
+// Pass the same Activity to [Automaton.StepWith] with [StepOptions.Activity] for every step of a simulation.
+//
+// Skipping is only done for automata that declare their predicates are local with [Automaton.SetLocality].
+// The grid is divided into square tiles, and a tile is only evaluated if a cell changed in the last step within the locality radius of the tile.
+// The cells of every other tile are copied unchanged.
+//
+// Every cell is evaluated, as normal, on the first step, when the grid changes size, when tracing with [StepOptions.Trace], for a [Graph],
+// and for the fast path described in [NewLifeLikeAutomaton]. If a predicate calls [Cell.Rand], the rule is stochastic, so a cell can change
+// even though nothing around it did. The Activity then falls back to evaluating every cell for the rest of the simulation.
+//
+// An Activity must only be used for successive steps of the same simulation, where the dst of each step is the src of the next.
+// If the grid is changed in between, call [Activity.Reset] first. The zero value is ready to use.
+type Activity struct {
+	width, height  int
+	tilesX, tilesY int
+	changed        []bool
+	valid          bool
+	stochastic     bool
+	evaluated      uint
+}
+
+// Reset forgets what changed in the last step, so the next step evaluates every cell.
+func (act *Activity) Reset() {
+	act.valid = false
+}
+
+// Stochastic reports whether a predicate called [Cell.Rand], so every cell is now evaluated on every step.
+func (act *Activity) Stochastic() bool {
+	return act.stochastic
+}
+
+// Evaluated returns the number of cells whose predicates were evaluated in the last step.
+func (act *Activity) Evaluated() uint {
+	return act.evaluated
+}
+
+// prepare sizes act for a width by height grid, forgetting what changed if the size is different.
+func (act *Activity) prepare(width, height int) {
+	if act.width == width && act.height == height && act.changed != nil {
+		return
+	}
+
+	act.width, act.height = width, height
+	act.tilesX = (width + activityTile - 1) / activityTile
+	act.tilesY = (height + activityTile - 1) / activityTile
+	act.changed = make([]bool, act.tilesX*act.tilesY)
+	act.valid = false
+}
+
+// active returns which tiles must be evaluated in the next step, given cells can see radius cells away, or nil if every tile must be.
+func (act *Activity) active(radius uint, toroidal bool) []bool {
+	if !act.valid || act.stochastic {
+		return nil
+	}
+
+	// how many tiles away a change can be seen from
+	reach := (int(radius) + activityTile - 1) / activityTile
+	if toroidal && (act.width%activityTile != 0 || act.height%activityTile != 0) {
+		// a change can wrap around into the tile before a narrower last tile
+		reach++
+	}
+
+	active := make([]bool, len(act.changed))
+	for tx := range act.tilesX {
+		for ty := range act.tilesY {
+			if !act.changed[tx*act.tilesY+ty] {
+				continue
+			}
+
+			for dx := -reach; dx <= reach; dx++ {
+				for dy := -reach; dy <= reach; dy++ {
+					x, y := tx+dx, ty+dy
+					if toroidal {
+						x, y = wrap(x, act.tilesX), wrap(y, act.tilesY)
+					} else if x < 0 || x >= act.tilesX || y < 0 || y >= act.tilesY {
+						continue
+					}
+					active[x*act.tilesY+y] = true
+				}
+			}
+		}
+	}
+
+	return active
+}
+
+// SetLocality declares that the predicates of this automaton are local: whether a cell changes depends only on the states of the cells within radius of it,
+// in both x and y, and on [Cell.Rand]. Predicates must not depend on anything else, such as the generation or a variable outside the predicate.
+//
+// This lets steps given an [Activity] skip the parts of the grid where nothing has changed recently. For example, a rule using [Cell.CountNeighbours] has a locality radius of 1.
+//
+// On a [Hexagonal] topology, radius may instead bound the offsets given to [Cell.Count] or [Cell.HexNeighbour], which reach further across the grid's offset rows.
+func (a *Automaton) SetLocality(radius uint) {
+	a.local = true
+	a.localityRadius = radius
+}
+
+// reach returns how far across the grid, in x and y, the predicates of a local automaton can see.
+func (a Automaton) reach() uint {
+	if a.topology == Hexagonal {
+		// an offset converted by Cell.Count or Cell.HexNeighbour moves up to half a cell further in x for every row it crosses
+		return a.localityRadius + (a.localityRadius+1)/2
+	}
+	return a.localityRadius
+}
+
+// GetLocality returns the locality radius declared with [Automaton.SetLocality], and whether one has been declared.
+func (a Automaton) GetLocality() (uint, bool) {
+	return a.localityRadius, a.local
+}
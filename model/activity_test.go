@@ -0,0 +1,202 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// stepTracked steps c for the given number of generations both with and without an Activity, failing if the results ever differ.
+// It returns the number of cells evaluated in the last tracked step.
+func stepTracked(t *testing.T, a *Automaton, c [][]uint, generations uint) (*Activity, uint) {
+	t.Helper()
+
+	act := &Activity{}
+	width, height := uint(len(c)), uint(len(c[0]))
+	tracked, untracked := NewGrid(width, height, 0), NewGrid(width, height, 0)
+	for x := range c {
+		copy(tracked[x], c[x])
+		copy(untracked[x], c[x])
+	}
+	trackedNext, untrackedNext := NewGrid(width, height, 0), NewGrid(width, height, 0)
+
+	for generation := range generations {
+		a.StepWith(tracked, trackedNext, StepOptions{Generation: generation, Activity: act})
+		a.StepWith(untracked, untrackedNext, StepOptions{Generation: generation})
+		tracked, trackedNext = trackedNext, tracked
+		untracked, untrackedNext = untrackedNext, untracked
+
+		if !reflect.DeepEqual(tracked, untracked) {
+			t.Fatalf("generation %v: tracked step differs from untracked step", generation+1)
+		}
+	}
+
+	return act, act.Evaluated()
+}
+
+func TestActivity_Glider(t *testing.T) {
+	boundaries := []Boundary{{Mode: Void}, {Mode: Toroidal}, {Mode: Reflective}, {Mode: Constant, OutsideState: 0}}
+	sizes := [][2]uint{{64, 64}, {100, 75}}
+
+	for _, boundary := range boundaries {
+		for _, size := range sizes {
+			for _, workers := range []uint{1, 3} {
+				t.Run(fmt.Sprintf("%+v, %vx%v, %v workers", boundary, size[0], size[1], workers), func(t *testing.T) {
+					a := newTestConways()
+					a.SetLocality(1)
+					a.SetWorkers(workers)
+					if err := a.SetBoundary(boundary); err != nil {
+						t.Fatalf("SetBoundary() error = %v", err)
+					}
+
+					// a glider heading for the bottom left corner, so it crosses tiles and meets the edges
+					c := NewGrid(size[0], size[1], 0)
+					c[20][22], c[21][22], c[22][22], c[22][21], c[21][20] = 1, 1, 1, 1, 1
+
+					act, evaluated := stepTracked(t, a, c, 120)
+					if act.Stochastic() {
+						t.Errorf("Activity.Stochastic() = true for a deterministic rule")
+					}
+					if total := size[0] * size[1]; evaluated >= total {
+						t.Errorf("Activity.Evaluated() = %v, want fewer than all %v cells", evaluated, total)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestActivity_Radius(t *testing.T) {
+	// a signal that travels 20 cells left every generation, so changes must be tracked more than one tile away
+	transitions := NewTransitionSet()
+	transitions.AddTransition(0, 1, func(cell Cell) bool {
+		right, err := cell.Neighbour(20, 0)
+		return err == nil && right == 1
+	})
+	transitions.AddTransition(1, 0, func(cell Cell) bool { return true })
+	a, err := NewAutomaton(transitions, []Rgb{{}, {R: 1}})
+	if err != nil {
+		t.Fatalf("NewAutomaton() error = %v", err)
+	}
+	a.SetLocality(20)
+
+	for _, boundary := range []Boundary{{Mode: Void}, {Mode: Toroidal}} {
+		t.Run(fmt.Sprintf("%+v", boundary), func(t *testing.T) {
+			if err := a.SetBoundary(boundary); err != nil {
+				t.Fatalf("SetBoundary() error = %v", err)
+			}
+
+			c := NewGrid(100, 20, 0)
+			c[99][5] = 1
+			stepTracked(t, a, c, 12)
+		})
+	}
+}
+
+func TestActivity_Hexagonal(t *testing.T) {
+	// a signal that jumps along a sheared offset, which Cell.Count converts to reach further across the grid's offset rows than the declared radius
+	far := Neighbourhood{{X: 16, Y: 16}}
+	transitions := NewTransitionSet()
+	transitions.AddTransition(0, 1, func(cell Cell) bool { return cell.Count(1, far) > 0 })
+	transitions.AddTransition(1, 0, func(cell Cell) bool { return true })
+	a, err := NewAutomaton(transitions, []Rgb{{}, {R: 1}})
+	if err != nil {
+		t.Fatalf("NewAutomaton() error = %v", err)
+	}
+	if err := a.SetTopology(Hexagonal); err != nil {
+		t.Fatalf("SetTopology() error = %v", err)
+	}
+	a.SetLocality(16)
+
+	c := NewGrid(128, 64, 0)
+	c[100][50] = 1
+	stepTracked(t, a, c, 3)
+}
+
+func TestActivity_Stochastic(t *testing.T) {
+	transitions := NewTransitionSet()
+	transitions.AddTransition(0, 1, func(cell Cell) bool { return cell.Rand().Float64() < 0.01 })
+	transitions.AddTransition(1, 0, func(cell Cell) bool { return cell.CountNeighbours(1, true) > 2 })
+	a, err := NewAutomaton(transitions, []Rgb{{}, {R: 1}})
+	if err != nil {
+		t.Fatalf("NewAutomaton() error = %v", err)
+	}
+	a.SetLocality(1)
+	a.SetSeed(7)
+
+	act, evaluated := stepTracked(t, a, NewGrid(40, 40, 0), 10)
+	if !act.Stochastic() {
+		t.Errorf("Activity.Stochastic() = false for a rule calling Cell.Rand")
+	}
+	if evaluated != 40*40 {
+		t.Errorf("Activity.Evaluated() = %v, want every cell evaluated", evaluated)
+	}
+}
+
+func TestActivity_NotLocal(t *testing.T) {
+	a := newTestConways()
+	if _, local := a.GetLocality(); local {
+		t.Fatalf("Automaton.GetLocality() reported a locality that was never declared")
+	}
+
+	c := NewGrid(64, 64, 0)
+	c[10][10], c[11][10], c[12][10] = 1, 1, 1
+
+	if _, evaluated := stepTracked(t, a, c, 5); evaluated != 64*64 {
+		t.Errorf("Activity.Evaluated() = %v, want every cell evaluated without a declared locality", evaluated)
+	}
+}
+
+func TestActivity_Reset(t *testing.T) {
+	a := newTestConways()
+	a.SetLocality(1)
+
+	act := &Activity{}
+	src, dst := NewGrid(64, 64, 0), NewGrid(64, 64, 0)
+	src[10][10], src[11][10], src[12][10] = 1, 1, 1
+
+	a.StepWith(src, dst, StepOptions{Activity: act})
+	a.StepWith(dst, src, StepOptions{Activity: act})
+	if act.Evaluated() == 64*64 {
+		t.Fatalf("Activity.Evaluated() = %v, want fewer than every cell", act.Evaluated())
+	}
+
+	// changing the grid behind the activity's back is only safe after a reset
+	src[50][50], src[51][50], src[52][50] = 1, 1, 1
+	act.Reset()
+	a.StepWith(src, dst, StepOptions{Activity: act})
+	if act.Evaluated() != 64*64 {
+		t.Errorf("Activity.Evaluated() = %v after Reset, want every cell", act.Evaluated())
+	}
+	if dst[51][51] != 1 {
+		t.Errorf("step after Reset missed a change made outside the simulation")
+	}
+}
+
+func TestRuleConstructors_Locality(t *testing.T) {
+	tests := []struct {
+		name       string
+		construct  func() (*Automaton, error)
+		wantRadius uint
+	}{
+		{"life", func() (*Automaton, error) { return ParseLifeRule("B3/S23") }, 1},
+		{"hensel", func() (*Automaton, error) { return ParseLifeRule("B2-a/S12") }, 1},
+		{"life-like", func() (*Automaton, error) { return NewLifeLikeAutomaton([]uint{3}, []uint{2, 3}) }, 1},
+		{"generations", func() (*Automaton, error) { return ParseGenerationsRule("B2/S/3") }, 1},
+		{"larger than life", func() (*Automaton, error) { return ParseLargerThanLifeRule("R5,C0,M1,S34..58,B34..45,NM") }, 5},
+		{"larger than life, von neumann", func() (*Automaton, error) { return ParseLargerThanLifeRule("R3,C2,M0,S2..4,B3,NN") }, 3},
+		{"elementary", func() (*Automaton, error) { return NewElementaryAutomaton(30) }, 1},
+		{"totalistic", func() (*Automaton, error) { return NewTotalisticAutomaton1D(3, 2, 1599) }, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := tt.construct()
+			if err != nil {
+				t.Fatalf("constructor error = %v", err)
+			}
+			if radius, local := a.GetLocality(); !local || radius != tt.wantRadius {
+				t.Errorf("GetLocality() = (%v, %v), want (%v, true)", radius, local, tt.wantRadius)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package model
 
 import (
 	"fmt"
+	"math/rand/v2"
+	"runtime"
 	"sync"
 )
 
@@ -15,6 +17,16 @@ type Automaton struct {
 	colouring     []Rgb
 	transitionSet TransitionSet
 	states        uint
+	boundary      Boundary
+	topology      Topology
+	graph         *Graph
+	workers       uint
+	seed          uint64
+	// local reports whether the predicates only depend on cells within localityRadius; see [Automaton.SetLocality]
+	local          bool
+	localityRadius uint
+	// bits, if not nil, lets the automaton be stepped by the bit packed fast path; see [NewLifeLikeAutomaton]
+	bits *bitRule
 }
 
 // at safely indexes the cell matrix c, returning an error if an off-grid value has been indexed.
@@ -32,39 +44,42 @@ func at(c [][]uint, x, y int) (uint, error) {
 //
 // Examples of how to set up an automaton are available in the [github.com/michael-ryan/cellularautomata/examples] package.
 func NewAutomaton(transitions TransitionSet, colouring []Rgb) (*Automaton, error) {
+	if err := checkAutomaton(transitions, func(t transition) uint { return t.NewState }, colouring); err != nil {
+		return nil, err
+	}
+
+	return &Automaton{states: uint(len(transitions)),
+		transitionSet: transitions,
+		colouring:     colouring,
+		seed:          rand.Uint64(),
+	}, nil
+}
+
+// checkAutomaton validates the transitions and colouring given to [NewAutomaton] or [NewAutomaton3D], where newState returns the state a transition leads to.
+func checkAutomaton[T any](transitions [][]T, newState func(T) uint, colouring []Rgb) error {
 	if len(transitions) != len(colouring) {
-		return nil, fmt.Errorf("mismatched lengths of transitions and colouring: %v != %v", len(transitions), len(colouring))
+		return fmt.Errorf("mismatched lengths of transitions and colouring: %v != %v", len(transitions), len(colouring))
 	}
 
 	if len(transitions) <= 1 {
-		return nil, fmt.Errorf("it does not make sense to create an automaton that describes 0 or 1 states")
+		return fmt.Errorf("it does not make sense to create an automaton that describes 0 or 1 states")
 	}
 
-	bad := false
 	for state, rgb := range colouring {
-		bad = bad || rgb.R > 1 || rgb.R < 0
-		bad = bad || rgb.G > 1 || rgb.G < 0
-		bad = bad || rgb.B > 1 || rgb.B < 0
-
-		if bad {
-			return nil, fmt.Errorf("colouring rule at index %v invalid, all values must be in the closed interval [0-1]: %+v", state, rgb)
+		if rgb.R > 1 || rgb.R < 0 || rgb.G > 1 || rgb.G < 0 || rgb.B > 1 || rgb.B < 0 {
+			return fmt.Errorf("colouring rule at index %v invalid, all values must be in the closed interval [0-1]: %+v", state, rgb)
 		}
 	}
 
-	states := uint(len(transitions))
-
 	for fromState, stateTransitions := range transitions {
 		for ruleNumber, t := range stateTransitions {
-			if t.NewState >= states {
-				return nil, fmt.Errorf("state index %v, rule index %v has invalid new state %v (max = len(transitions) - 1 = %v)", fromState, ruleNumber, t.NewState, len(transitions)-1)
+			if newState(t) >= uint(len(transitions)) {
+				return fmt.Errorf("state index %v, rule index %v has invalid new state %v (max = len(transitions) - 1 = %v)", fromState, ruleNumber, newState(t), len(transitions)-1)
 			}
 		}
 	}
 
-	return &Automaton{states: states,
-		transitionSet: transitions,
-		colouring:     colouring,
-	}, nil
+	return nil
 }
 
 // States describes the number of states defined in this automaton.
@@ -72,6 +87,91 @@ func (a Automaton) CountStates() uint {
 	return a.states
 }
 
+// SetBoundary sets the boundary conditions used by [Cell.Neighbour] and [Cell.CountNeighbours] during [Automaton.Step].
+// By default, an Automaton uses the [Void] boundary mode.
+func (a *Automaton) SetBoundary(b Boundary) error {
+	if err := checkBoundary(b, a.states); err != nil {
+		return err
+	}
+
+	a.boundary = b
+	return nil
+}
+
+// checkBoundary validates a boundary for an automaton with the given number of states.
+func checkBoundary(b Boundary, states uint) error {
+	if b.Mode > Constant {
+		return fmt.Errorf("unknown boundary mode %v", b.Mode)
+	}
+
+	if b.Mode == Constant && b.OutsideState >= states {
+		return fmt.Errorf("outside state %v invalid, there are only %v states defined (max = %v)", b.OutsideState, states, states-1)
+	}
+
+	return nil
+}
+
+// GetBoundary returns the boundary conditions of this automaton.
+func (a Automaton) GetBoundary() Boundary {
+	return a.boundary
+}
+
+// SetTopology sets the arrangement of cells on the grid, which decides the neighbours counted by [Cell.CountNeighbours] during [Automaton.Step],
+// and how the grid is drawn by [github.com/michael-ryan/cellularautomata/v2.Launch].
+// By default, an Automaton uses the [Square] topology.
+func (a *Automaton) SetTopology(t Topology) error {
+	if t > Hexagonal {
+		return fmt.Errorf("unknown topology %v", t)
+	}
+
+	a.topology = t
+	return nil
+}
+
+// GetTopology returns the arrangement of cells on the grid of this automaton.
+func (a Automaton) GetTopology() Topology {
+	return a.topology
+}
+
+// SetGraph makes this automaton simulate cells on the nodes of g, rather than on a grid. See [Graph].
+// The automaton's [Boundary] and [Topology] are ignored while it is on a graph.
+//
+// Passing nil returns the automaton to a grid. The graph must not be changed while it is being simulated.
+func (a *Automaton) SetGraph(g *Graph) {
+	a.graph = g
+}
+
+// GetGraph returns the graph this automaton is simulated on, or nil if it is simulated on a grid.
+func (a Automaton) GetGraph() *Graph {
+	return a.graph
+}
+
+// SetWorkers sets the number of workers that share the grid during [Automaton.Step].
+// If n is 0, which is the default, one worker is used per available CPU, as reported by [runtime.GOMAXPROCS].
+func (a *Automaton) SetWorkers(n uint) {
+	a.workers = n
+}
+
+// countWorkers resolves the number of workers set with [Automaton.SetWorkers] or [Automaton3D.SetWorkers] into the number to use for a single step.
+func countWorkers(workers uint) int {
+	if workers == 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return int(workers)
+}
+
+// SetSeed sets the seed of the random number generators returned by [Cell.Rand].
+// By default, every Automaton is given a random seed, so stochastic automata behave differently each time the program runs.
+// Set a seed, or record the default one with [Automaton.GetSeed], to make a simulation reproducible.
+func (a *Automaton) SetSeed(seed uint64) {
+	a.seed = seed
+}
+
+// GetSeed returns the seed of the random number generators returned by [Cell.Rand].
+func (a Automaton) GetSeed() uint64 {
+	return a.seed
+}
+
 // Colouring is an array of RGB values, instructing the renderer what colour to show a given state. State n should have its colour described in Colouring[n].
 func (a Automaton) GetColouring() []Rgb {
 	colouringCopy := make([]Rgb, len(a.colouring))
@@ -81,65 +181,215 @@ func (a Automaton) GetColouring() []Rgb {
 
 // Step simulates a single time step.
 // All cells will have their transition rules checked, and a new array is returned representing the new states of all the cells.
+// [Cell.Rand] is seeded as for generation 0, so stochastic automata stepped repeatedly should use [Automaton.StepWith] with [StepOptions.Generation] instead.
 //
 // This is a pure function, and will not modify any state, so it is safe to call manually.
 // However, it is not needed to call this manually if using the provided graphical rendering package [github.com/michael-ryan/cellularautomata].
 func (a Automaton) Step(c [][]uint) [][]uint {
-	new := make([][]uint, len(c))
-	for x := range len(new) {
-		new[x] = make([]uint, len(c[0]))
-		for y := range len(new[x]) {
-			new[x][y] = c[x][y]
+	if len(c) == 0 {
+		return [][]uint{}
+	}
+
+	new := NewGrid(uint(len(c)), uint(len(c[0])), 0)
+	a.StepInto(c, new)
+	return new
+}
+
+// StepInto simulates a single time step, reading the current states from src and writing the new states into dst.
+// Both grids must have the same dimensions, and they must not share any memory. Like [Automaton.Step], it steps from generation 0.
+//
+// This is the allocation-free counterpart to [Automaton.Step], intended for double buffering: allocate two grids with [NewGrid] and swap them after every call.
+//
+// The grid is split into bands of columns, and each band is processed by one of a fixed number of workers, set by [Automaton.SetWorkers].
+func (a Automaton) StepInto(src, dst [][]uint) {
+	a.StepWith(src, dst, StepOptions{})
+}
+
+// StepOptions holds optional extras for [Automaton.StepWith].
+type StepOptions struct {
+	// Trace, if not nil, is filled with a record of which transitions fired during the step.
+	Trace *Trace
+	// Generation is the generation being stepped from, used to seed [Cell.Rand].
+	// [Automaton.Step] and [Automaton.StepInto] always use generation 0, so stochastic automata stepped repeatedly should use this instead to avoid drawing the same numbers every generation.
+	Generation uint
+	// Activity, if not nil, tracks which parts of the grid changed, so that later steps can skip the parts that cannot change. See [Activity].
+	Activity *Activity
+}
+
+// StepWith behaves like [Automaton.StepInto], with extra behaviour enabled by opts.
+func (a Automaton) StepWith(src, dst [][]uint, opts StepOptions) {
+	width := len(src)
+	if width == 0 {
+		return
+	}
+	height := len(src[0])
+
+	if len(dst) != width || len(dst[0]) != height {
+		panic(fmt.Sprintf("mismatched grid dimensions: src is %vx%v but dst is %vx%v", width, height, len(dst), len(dst[0])))
+	}
+
+	if a.graph != nil && (uint(width) != a.graph.CountNodes() || height != 1) {
+		panic(fmt.Sprintf("mismatched grid dimensions: grid is %vx%v but the graph needs %vx1", width, height, a.graph.CountNodes()))
+	}
+
+	act := opts.Activity
+	if act != nil {
+		act.prepare(width, height)
+	}
+
+	if a.canStepBits(opts) {
+		a.stepBits(src, dst)
+		if act != nil {
+			act.valid = false
+			act.evaluated = uint(width * height)
 		}
+		return
 	}
 
-	type edit struct {
-		x, y     int
-		newState uint
+	trace := opts.Trace
+	if trace != nil {
+		trace.reset(a.transitionSet, uint(width), uint(height))
 	}
 
+	// changes are only tracked when every cell that could change is sure to be evaluated, and its rule recorded if tracing
+	tracking := act != nil && a.local && a.graph == nil && trace == nil
+	var active, changed []bool
+	if tracking {
+		active = act.active(a.reach(), a.boundary.Mode == Toroidal)
+		changed = make([]bool, len(act.changed))
+	} else if act != nil {
+		act.valid = false
+	}
+
+	workers := countWorkers(a.workers)
+	if workers > width {
+		workers = width
+	}
+	bandWidth := (width + workers - 1) / workers
+	if tracking {
+		// bands are whole tiles wide, so no two workers record changes in the same tile
+		bandWidth = (bandWidth + activityTile - 1) / activityTile * activityTile
+	}
+
+	var evaluated uint
+	stochastic := false
+
 	wg := sync.WaitGroup{}
-	editChan := make(chan edit)
-
-	for x := range len(c) {
-		for y := range len(c[0]) {
-			// run each cell compute in its own goroutine
-			wg.Add(1)
-			go func(x, y int, c [][]uint, model TransitionSet, editChan chan<- edit) {
-				defer wg.Done()
-				thisCell, err := at(c, x, y)
-				if err != nil {
-					panic("Something has gone very wrong. We indexed outside of the grid in the step function.")
-				}
+	mu := sync.Mutex{}
+	for start := 0; start < width; start += bandWidth {
+		end := min(start+bandWidth, width)
 
-				cell := Cell{
-					x:     x,
-					y:     y,
-					cells: c,
-				}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+
+			// each worker counts into its own table, to avoid contention, and merges it into the trace at the end
+			var fired [][]uint
+			if trace != nil {
+				fired = newFiredTable(a.transitionSet)
+			}
+
+			rng := newCellRand()
+			workerEvaluated := uint(0)
+			workerStochastic := false
+
+			for x := start; x < end; x++ {
+				for y0 := 0; y0 < height; y0 += activityTile {
+					y1 := min(y0+activityTile, height)
+					tile := (x/activityTile)*((height+activityTile-1)/activityTile) + y0/activityTile
 
-				for _, t := range model[thisCell] {
-					if t.Predicate(cell) {
-						editChan <- edit{
-							x:        x,
-							y:        y,
-							newState: t.NewState,
+					if active != nil && !active[tile] {
+						copy(dst[x][y0:y1], src[x][y0:y1])
+						continue
+					}
+
+					for y := y0; y < y1; y++ {
+						rng.reset(a.seed, opts.Generation, x, y)
+						state, rule := a.next(src, x, y, rng)
+						dst[x][y] = state
+						workerEvaluated++
+
+						if tracking {
+							workerStochastic = workerStochastic || rng.seeded
+							if state != src[x][y] {
+								changed[tile] = true
+							}
+						}
+
+						if trace == nil {
+							continue
+						}
+						if rule >= 0 {
+							fired[src[x][y]][rule]++
+						}
+						if trace.Rules != nil {
+							trace.Rules[x][y] = rule
 						}
-						return
 					}
 				}
-			}(x, y, c, a.transitionSet, editChan)
+			}
+
+			mu.Lock()
+			if trace != nil {
+				trace.merge(fired)
+			}
+			evaluated += workerEvaluated
+			stochastic = stochastic || workerStochastic
+			mu.Unlock()
+		}(start, end)
+	}
+	wg.Wait()
+
+	if act != nil {
+		act.evaluated = evaluated
+	}
+	if tracking {
+		act.changed = changed
+		act.valid = true
+		act.stochastic = act.stochastic || stochastic
+	}
+}
+
+// next computes the state of the cell at (x, y) in the following generation, along with the index of the rule that decided it.
+// The rule index is -1 if no rule fired and the cell kept its state.
+func (a Automaton) next(c [][]uint, x, y int, rng *cellRand) (uint, int) {
+	thisCell := c[x][y]
+
+	cell := Cell{
+		x:        x,
+		y:        y,
+		cells:    c,
+		boundary: a.boundary,
+		topology: a.topology,
+		graph:    a.graph,
+		rand:     rng,
+	}
+
+	for i, t := range a.transitionSet[thisCell] {
+		if t.Predicate(cell) {
+			return t.NewState, i
 		}
 	}
 
-	go func() {
-		wg.Wait()
-		close(editChan)
-	}()
+	return thisCell, -1
+}
+
+// NewGrid allocates a width by height grid of cells, indexed as grid[x][y], with every cell set to state.
+//
+// All columns share a single contiguous backing array, which keeps the grid cache friendly during [Automaton.StepInto].
+func NewGrid(width, height, state uint) [][]uint {
+	flat := make([]uint, width*height)
+	if state != 0 {
+		for i := range flat {
+			flat[i] = state
+		}
+	}
 
-	for edit := range editChan {
-		new[edit.x][edit.y] = edit.newState
+	grid := make([][]uint, width)
+	for x := range grid {
+		start := uint(x) * height
+		grid[x] = flat[start : start+height : start+height]
 	}
 
-	return new
+	return grid
 }
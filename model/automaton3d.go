@@ -0,0 +1,148 @@
+package model
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sync"
+)
+
+// Automaton3D is the three-dimensional counterpart to [Automaton]. You should use the [NewAutomaton3D] function to create one.
+type Automaton3D struct {
+	colouring     []Rgb
+	transitionSet TransitionSet3D
+	states        uint
+	boundary      Boundary
+	workers       uint
+	seed          uint64
+}
+
+// NewAutomaton3D constructs a new Automaton3D from a given TransitionSet3D and colouring setup, following the same rules as [NewAutomaton].
+func NewAutomaton3D(transitions TransitionSet3D, colouring []Rgb) (*Automaton3D, error) {
+	if err := checkAutomaton(transitions, func(t transition3D) uint { return t.NewState }, colouring); err != nil {
+		return nil, err
+	}
+
+	return &Automaton3D{states: uint(len(transitions)),
+		transitionSet: transitions,
+		colouring:     colouring,
+		seed:          rand.Uint64(),
+	}, nil
+}
+
+// CountStates describes the number of states defined in this automaton.
+func (a Automaton3D) CountStates() uint {
+	return a.states
+}
+
+// SetBoundary sets the boundary conditions used by [Cell3D.Neighbour] and [Cell3D.CountNeighbours] during [Automaton3D.Step].
+// Boundaries apply along all three axes. By default, an Automaton3D uses the [Void] boundary mode.
+func (a *Automaton3D) SetBoundary(b Boundary) error {
+	if err := checkBoundary(b, a.states); err != nil {
+		return err
+	}
+
+	a.boundary = b
+	return nil
+}
+
+// GetBoundary returns the boundary conditions of this automaton.
+func (a Automaton3D) GetBoundary() Boundary {
+	return a.boundary
+}
+
+// SetWorkers sets the number of workers that share the grid during [Automaton3D.Step], as [Automaton.SetWorkers] does.
+func (a *Automaton3D) SetWorkers(n uint) {
+	a.workers = n
+}
+
+// SetSeed sets the seed of the random number generators returned by [Cell3D.Rand], as [Automaton.SetSeed] does.
+func (a *Automaton3D) SetSeed(seed uint64) {
+	a.seed = seed
+}
+
+// GetSeed returns the seed of the random number generators returned by [Cell3D.Rand].
+func (a Automaton3D) GetSeed() uint64 {
+	return a.seed
+}
+
+// GetColouring returns a copy of the render colour of each state. State n has its colour described in GetColouring()[n].
+func (a Automaton3D) GetColouring() []Rgb {
+	colouringCopy := make([]Rgb, len(a.colouring))
+	copy(colouringCopy, a.colouring)
+	return colouringCopy
+}
+
+// Step simulates a single time step from the given generation, which is used to seed [Cell3D.Rand], returning a new grid representing the new states of all the cells.
+// Unlike [Automaton.Step], the generation must always be given, so stochastic automata stepped repeatedly do not draw the same numbers every generation.
+//
+// This is a pure function, and will not modify any state.
+func (a Automaton3D) Step(c Grid3D, generation uint) Grid3D {
+	width, height, depth := c.Dimensions()
+	new := NewGrid3D(width, height, depth, 0)
+	a.StepInto(c, new, generation)
+	return new
+}
+
+// StepInto behaves like [Automaton3D.Step], reading the current states from src and writing the new states into dst.
+// Both grids must have the same dimensions, and they must not share any memory.
+//
+// The planes of the grid are shared between a fixed number of workers, set by [Automaton3D.SetWorkers].
+func (a Automaton3D) StepInto(src, dst Grid3D, generation uint) {
+	width, height, depth := src.Dimensions()
+	if dstWidth, dstHeight, dstDepth := dst.Dimensions(); dstWidth != width || dstHeight != height || dstDepth != depth {
+		panic(fmt.Sprintf("mismatched grid dimensions: src is %vx%vx%v but dst is %vx%vx%v", width, height, depth, dstWidth, dstHeight, dstDepth))
+	}
+	if width == 0 || height == 0 || depth == 0 {
+		return
+	}
+
+	workers := countWorkers(a.workers)
+	if workers > int(depth) {
+		workers = int(depth)
+	}
+	bandDepth := (int(depth) + workers - 1) / workers
+
+	wg := sync.WaitGroup{}
+	for start := 0; start < int(depth); start += bandDepth {
+		end := min(start+bandDepth, int(depth))
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+
+			rng := newCellRand()
+
+			for z := start; z < end; z++ {
+				for x := range int(width) {
+					for y := range int(height) {
+						rng.reset3D(a.seed, generation, x, y, z)
+						dst[z][x][y] = a.next(src, x, y, z, rng)
+					}
+				}
+			}
+		}(start, end)
+	}
+	wg.Wait()
+}
+
+// next computes the state of the cell at (x, y, z) in the following generation.
+func (a Automaton3D) next(c Grid3D, x, y, z int, rng *cellRand) uint {
+	thisCell := c[z][x][y]
+
+	cell := Cell3D{
+		x:        x,
+		y:        y,
+		z:        z,
+		cells:    c,
+		boundary: a.boundary,
+		rand:     rng,
+	}
+
+	for _, t := range a.transitionSet[thisCell] {
+		if t.Predicate(cell) {
+			return t.NewState
+		}
+	}
+
+	return thisCell
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewAutomaton3D(t *testing.T) {
+	colouring := []Rgb{{R: 0, G: 0, B: 0}, {R: 1, G: 1, B: 1}}
+
+	valid := NewTransitionSet3D()
+	valid.AddTransition(0, 1, func(cell Cell3D) bool { return true })
+
+	tests := []struct {
+		name        string
+		transitions TransitionSet3D
+		colouring   []Rgb
+		wantErr     bool
+	}{
+		{name: "valid", transitions: valid, colouring: colouring, wantErr: false},
+		{name: "mismatched colouring", transitions: valid, colouring: colouring[:1], wantErr: true},
+		{name: "bad colour", transitions: valid, colouring: []Rgb{{R: 2}, {}}, wantErr: true},
+		{name: "one state", transitions: TransitionSet3D{{}}, colouring: colouring[:1], wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAutomaton3D(tt.transitions, tt.colouring)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewAutomaton3D() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAutomaton3D_Step(t *testing.T) {
+	// a single cell dies, and gives birth to the six cells sharing a face with it
+	a, err := ParseLife3DRule("/1/2/N")
+	if err != nil {
+		t.Fatalf("ParseLife3DRule() error = %v", err)
+	}
+
+	c := NewGrid3D(3, 3, 3, 0)
+	c[1][1][1] = 1
+
+	want := NewGrid3D(3, 3, 3, 0)
+	want[0][1][1] = 1
+	want[2][1][1] = 1
+	want[1][0][1] = 1
+	want[1][2][1] = 1
+	want[1][1][0] = 1
+	want[1][1][2] = 1
+
+	for _, workers := range []uint{1, 2, 5} {
+		a.SetWorkers(workers)
+		if got := a.Step(c, 0); !reflect.DeepEqual(got, want) {
+			t.Errorf("Step() with %v workers = %v, want %v", workers, got, want)
+		}
+	}
+}
+
+func TestAutomaton3D_StepInto_Rand(t *testing.T) {
+	transitions := NewTransitionSet3D()
+	transitions.AddTransition(0, 1, func(cell Cell3D) bool { return cell.Rand().IntN(2) == 0 })
+	a, err := NewAutomaton3D(transitions, []Rgb{{}, {R: 1, G: 1, B: 1}})
+	if err != nil {
+		t.Fatalf("NewAutomaton3D() error = %v", err)
+	}
+	a.SetSeed(42)
+
+	src := NewGrid3D(4, 4, 4, 0)
+	first, second := NewGrid3D(4, 4, 4, 0), NewGrid3D(4, 4, 4, 0)
+	a.StepInto(src, first, 3)
+	a.StepInto(src, second, 3)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("StepInto() with the same seed and generation gave different results")
+	}
+
+	a.StepInto(src, second, 4)
+	if reflect.DeepEqual(first, second) {
+		t.Errorf("StepInto() with different generations gave the same results")
+	}
+}
@@ -1,7 +1,10 @@
 package model
 
 import (
+	"fmt"
+	"math/rand"
 	"reflect"
+	"sync"
 	"testing"
 )
 
@@ -203,6 +206,55 @@ func TestAutomaton_Step(t *testing.T) {
 			},
 			want: [][]uint{{0, 1}, {1, 0}},
 		},
+		{
+			name: "toroidal wrap",
+			args: args{
+				c: [][]uint{{0, 0, 0}, {0, 0, 0}, {0, 0, 1}},
+				createAutomaton: func() *Automaton {
+					t := NewTransitionSet()
+					t.AddTransition(0, 1, func(cell Cell) bool {
+						// copy the cell to the left
+						left, err := cell.Neighbour(-1, 0)
+						return err == nil && left == 1
+					})
+					t.AddTransition(1, 0, func(cell Cell) bool { return true })
+
+					c := []Rgb{{R: 0, G: 0, B: 0}, {R: 1, G: 1, B: 1}}
+
+					a, _ := NewAutomaton(t, c)
+					a.SetBoundary(Boundary{Mode: Toroidal})
+					return a
+				},
+			},
+			want: [][]uint{{0, 0, 1}, {0, 0, 0}, {0, 0, 0}},
+		},
+		{
+			name: "constant outside state",
+			args: args{
+				c: [][]uint{{0, 0}, {0, 0}},
+				createAutomaton: func() *Automaton {
+					t := NewTransitionSet()
+					t.AddTransition(0, 1, func(cell Cell) bool { return cell.CountNeighbours(1, false) > 0 })
+
+					c := []Rgb{{R: 0, G: 0, B: 0}, {R: 1, G: 1, B: 1}}
+
+					a, _ := NewAutomaton(t, c)
+					a.SetBoundary(Boundary{Mode: Constant, OutsideState: 1})
+					return a
+				},
+			},
+			want: [][]uint{{1, 1}, {1, 1}},
+		},
+		{
+			name: "empty grid",
+			args: args{
+				c: [][]uint{},
+				createAutomaton: func() *Automaton {
+					return newTestConways()
+				},
+			},
+			want: [][]uint{},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -213,3 +265,214 @@ func TestAutomaton_Step(t *testing.T) {
 		})
 	}
 }
+
+func TestAutomaton_SetBoundary(t *testing.T) {
+	tests := []struct {
+		name     string
+		boundary Boundary
+		wantErr  bool
+	}{
+		{
+			name:     "void",
+			boundary: Boundary{Mode: Void},
+			wantErr:  false,
+		},
+		{
+			name:     "toroidal",
+			boundary: Boundary{Mode: Toroidal},
+			wantErr:  false,
+		},
+		{
+			name:     "constant",
+			boundary: Boundary{Mode: Constant, OutsideState: 1},
+			wantErr:  false,
+		},
+		{
+			name:     "constant invalid state",
+			boundary: Boundary{Mode: Constant, OutsideState: 2},
+			wantErr:  true,
+		},
+		{
+			name:     "unknown mode",
+			boundary: Boundary{Mode: Constant + 1},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := NewTransitionSet()
+			ts.AddTransition(0, 1, func(cell Cell) bool { return true })
+			a, err := NewAutomaton(ts, []Rgb{{R: 0, G: 0, B: 0}, {R: 1, G: 1, B: 1}})
+			if err != nil {
+				t.Errorf("Error during creation of test automaton = %v", err)
+				return
+			}
+			err = a.SetBoundary(tt.boundary)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Automaton.SetBoundary() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && a.GetBoundary() != tt.boundary {
+				t.Errorf("Automaton.GetBoundary() = %v, want %v", a.GetBoundary(), tt.boundary)
+			}
+
+			ts3D := NewTransitionSet3D()
+			ts3D.AddTransition(0, 1, func(cell Cell3D) bool { return true })
+			a3D, err := NewAutomaton3D(ts3D, []Rgb{{R: 0, G: 0, B: 0}, {R: 1, G: 1, B: 1}})
+			if err != nil {
+				t.Fatalf("Error during creation of test automaton = %v", err)
+			}
+			if err := a3D.SetBoundary(tt.boundary); (err != nil) != tt.wantErr {
+				t.Errorf("Automaton3D.SetBoundary() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewGrid(t *testing.T) {
+	grid := NewGrid(3, 2, 4)
+
+	if len(grid) != 3 {
+		t.Fatalf("len(NewGrid()) = %v, want %v", len(grid), 3)
+	}
+	for x := range grid {
+		if !reflect.DeepEqual(grid[x], []uint{4, 4}) {
+			t.Errorf("NewGrid()[%v] = %v, want %v", x, grid[x], []uint{4, 4})
+		}
+	}
+
+	// columns share a backing array, so appending to one must not clobber the next
+	_ = append(grid[0], 9)
+	if grid[1][0] != 4 {
+		t.Errorf("append to column 0 overwrote column 1: %v", grid[1])
+	}
+}
+
+func TestAutomaton_StepInto(t *testing.T) {
+	const width, height = 37, 23
+
+	src := NewGrid(width, height, 0)
+	r := rand.New(rand.NewSource(1))
+	for x := range src {
+		for y := range src[x] {
+			src[x][y] = uint(r.Intn(2))
+		}
+	}
+
+	a := newTestConways()
+	a.SetWorkers(1)
+	want := a.Step(src)
+
+	for _, workers := range []uint{0, 2, 3, 8, 64} {
+		t.Run(fmt.Sprintf("%v workers", workers), func(t *testing.T) {
+			a.SetWorkers(workers)
+			dst := NewGrid(width, height, 0)
+			a.StepInto(src, dst)
+			if !reflect.DeepEqual(dst, want) {
+				t.Errorf("Automaton.StepInto() with %v workers differs from a single worker", workers)
+			}
+		})
+	}
+}
+
+func newTestConways() *Automaton {
+	t := NewTransitionSet()
+	t.AddTransition(0, 1, func(c Cell) bool { return c.CountNeighbours(1, true) == 3 })
+	t.AddTransition(1, 0, func(c Cell) bool {
+		n := c.CountNeighbours(1, true)
+		return n < 2 || n > 3
+	})
+
+	a, _ := NewAutomaton(t, []Rgb{{R: 0, G: 0, B: 0}, {R: 1, G: 1, B: 1}})
+	return a
+}
+
+func newBenchmarkGrid(width, height uint) [][]uint {
+	grid := NewGrid(width, height, 0)
+	r := rand.New(rand.NewSource(1))
+	for x := range grid {
+		for y := range grid[x] {
+			grid[x][y] = uint(r.Intn(2))
+		}
+	}
+	return grid
+}
+
+// stepGoroutinePerCell is the original stepping engine, which spawned a goroutine per cell and funnelled every change through a channel.
+// It is kept here as a baseline for the benchmarks below.
+func stepGoroutinePerCell(a *Automaton, c [][]uint) [][]uint {
+	new := make([][]uint, len(c))
+	for x := range new {
+		new[x] = make([]uint, len(c[0]))
+		copy(new[x], c[x])
+	}
+
+	type edit struct {
+		x, y     int
+		newState uint
+	}
+
+	wg := sync.WaitGroup{}
+	editChan := make(chan edit)
+
+	for x := range len(c) {
+		for y := range len(c[0]) {
+			wg.Add(1)
+			go func(x, y int) {
+				defer wg.Done()
+				if state, _ := a.next(c, x, y, nil); state != c[x][y] {
+					editChan <- edit{x: x, y: y, newState: state}
+				}
+			}(x, y)
+		}
+	}
+
+	go func() {
+		wg.Wait()
+		close(editChan)
+	}()
+
+	for edit := range editChan {
+		new[edit.x][edit.y] = edit.newState
+	}
+
+	return new
+}
+
+func BenchmarkAutomaton_Step(b *testing.B) {
+	src := newBenchmarkGrid(1024, 1024)
+
+	b.Run("goroutine per cell", func(b *testing.B) {
+		a := newTestConways()
+		for range b.N {
+			stepGoroutinePerCell(a, src)
+		}
+	})
+
+	for _, workers := range []uint{1, 0} {
+		name := fmt.Sprintf("%v workers", workers)
+		if workers == 0 {
+			name = "default workers"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			a := newTestConways()
+			a.SetWorkers(workers)
+			dst := NewGrid(1024, 1024, 0)
+			b.ResetTimer()
+			for range b.N {
+				a.StepInto(src, dst)
+			}
+		})
+
+		b.Run(name+", bit packed", func(b *testing.B) {
+			a, _ := NewLifeLikeAutomaton([]uint{3}, []uint{2, 3})
+			a.SetWorkers(workers)
+			dst := NewGrid(1024, 1024, 0)
+			b.ResetTimer()
+			for range b.N {
+				a.StepInto(src, dst)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestNewLifeLikeAutomaton(t *testing.T) {
+	tests := []struct {
+		name     string
+		birth    []uint
+		survival []uint
+		wantErr  bool
+	}{
+		{name: "conway", birth: []uint{3}, survival: []uint{2, 3}, wantErr: false},
+		{name: "empty", birth: nil, survival: nil, wantErr: false},
+		{name: "birth too large", birth: []uint{9}, survival: nil, wantErr: true},
+		{name: "survival too large", birth: nil, survival: []uint{10}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewLifeLikeAutomaton(tt.birth, tt.survival)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewLifeLikeAutomaton() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && a.CountStates() != 2 {
+				t.Errorf("NewLifeLikeAutomaton().CountStates() = %v, want 2", a.CountStates())
+			}
+		})
+	}
+}
+
+// TestAutomaton_stepBits checks that the bit packed fast path gives exactly the same results as calling the predicates.
+func TestAutomaton_stepBits(t *testing.T) {
+	rules := []struct {
+		name           string
+		birth, survive []uint
+	}{
+		{name: "conway", birth: []uint{3}, survive: []uint{2, 3}},
+		{name: "highlife", birth: []uint{3, 6}, survive: []uint{2, 3}},
+		{name: "seeds", birth: []uint{2}, survive: nil},
+		{name: "birth on zero", birth: []uint{0, 1}, survive: []uint{8}},
+		{name: "everything", birth: []uint{0, 1, 2, 3, 4, 5, 6, 7, 8}, survive: []uint{0, 1, 2, 3, 4, 5, 6, 7, 8}},
+	}
+	boundaries := []Boundary{
+		{Mode: Void},
+		{Mode: Toroidal},
+		{Mode: Reflective},
+		{Mode: Constant, OutsideState: 0},
+		{Mode: Constant, OutsideState: 1},
+	}
+	sizes := [][2]uint{{1, 1}, {3, 1}, {1, 5}, {7, 62}, {5, 63}, {4, 64}, {9, 65}, {3, 130}}
+
+	for _, rule := range rules {
+		for _, boundary := range boundaries {
+			for _, size := range sizes {
+				name := fmt.Sprintf("%v, boundary %+v, %vx%v", rule.name, boundary, size[0], size[1])
+				t.Run(name, func(t *testing.T) {
+					fast, err := NewLifeLikeAutomaton(rule.birth, rule.survive)
+					if err != nil {
+						t.Fatalf("NewLifeLikeAutomaton() error = %v", err)
+					}
+					if err := fast.SetBoundary(boundary); err != nil {
+						t.Fatalf("SetBoundary() error = %v", err)
+					}
+					fast.SetWorkers(2)
+
+					slow := *fast
+					slow.bits = nil
+
+					c := newBenchmarkGrid(size[0], size[1])
+					for generation := range 4 {
+						want := slow.Step(c)
+						got := fast.Step(c)
+						if !reflect.DeepEqual(got, want) {
+							t.Fatalf("generation %v: fast path gave %v, want %v", generation+1, got, want)
+						}
+						c = want
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestParseLifeRule_bits(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     string
+		wantBits bool
+	}{
+		{name: "conway", rule: "B3/S23", wantBits: true},
+		{name: "hensel letters", rule: "B2-a/S12", wantBits: false},
+		{name: "hensel letters covering a whole count", rule: "B2aceikn/S", wantBits: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := ParseLifeRule(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseLifeRule() error = %v", err)
+			}
+			if got := a.bits != nil; got != tt.wantBits {
+				t.Errorf("ParseLifeRule() uses the fast path = %v, want %v", got, tt.wantBits)
+			}
+		})
+	}
+}
+
+func TestAutomaton_stepBits_Trace(t *testing.T) {
+	a, err := NewLifeLikeAutomaton([]uint{3}, []uint{2, 3})
+	if err != nil {
+		t.Fatalf("NewLifeLikeAutomaton() error = %v", err)
+	}
+
+	// a blinker, so one birth and one death fire on each side
+	src := NewGrid(3, 3, 0)
+	src[0][1], src[1][1], src[2][1] = 1, 1, 1
+
+	trace := &Trace{}
+	dst := NewGrid(3, 3, 0)
+	a.StepWith(src, dst, StepOptions{Trace: trace})
+
+	if !a.canStepBits(StepOptions{}) || a.canStepBits(StepOptions{Trace: trace}) {
+		t.Errorf("tracing should fall back to calling the predicates")
+	}
+	if got := trace.Fired[0][0] + trace.Fired[1][0]; got != 4 {
+		t.Errorf("trace recorded %v transitions, want 4", got)
+	}
+	if want := a.Step(src); !reflect.DeepEqual(dst, want) {
+		t.Errorf("traced step = %v, want %v", dst, want)
+	}
+}
@@ -0,0 +1,63 @@
+package model
+
+import "fmt"
+
+// BoundaryMode describes how an [Automaton] treats locations beyond the edge of the grid.
+type BoundaryMode uint
+
+const (
+	// Void treats off-grid locations as having no state. Querying them with [Cell.Neighbour] returns an error, and they never contribute to [Cell.CountNeighbours].
+	// This is the default.
+	Void BoundaryMode = iota
+	// Toroidal wraps the grid around on itself, so the left edge neighbours the right edge and the bottom edge neighbours the top edge.
+	Toroidal
+	// Reflective mirrors the grid about its edges, so the location just beyond an edge has the same state as the edge cell itself.
+	Reflective
+	// Constant treats every off-grid location as being in a fixed state, given by [Boundary.OutsideState].
+	Constant
+)
+
+// Boundary describes the boundary conditions of an [Automaton]. Apply it with [Automaton.SetBoundary].
+type Boundary struct {
+	Mode BoundaryMode
+	// OutsideState is the state of every off-grid location when Mode is [Constant]. It is ignored for all other modes.
+	OutsideState uint
+}
+
+// at indexes the cell matrix c, resolving off-grid locations according to this boundary.
+// An error is only returned if the location is off-grid and the boundary does not give it a state.
+func (b Boundary) at(c [][]uint, x, y int) (uint, error) {
+	if len(c) == 0 || len(c[0]) == 0 {
+		return 0, fmt.Errorf("indexed empty grid")
+	}
+
+	width, height := len(c), len(c[0])
+	if x >= 0 && x < width && y >= 0 && y < height {
+		return c[x][y], nil
+	}
+
+	switch b.Mode {
+	case Toroidal:
+		return c[wrap(x, width)][wrap(y, height)], nil
+	case Reflective:
+		return c[mirror(x, width)][mirror(y, height)], nil
+	case Constant:
+		return b.OutsideState, nil
+	default:
+		return at(c, x, y)
+	}
+}
+
+// wrap maps i onto [0, n) as if the axis repeated forever.
+func wrap(i, n int) int {
+	return ((i % n) + n) % n
+}
+
+// mirror maps i onto [0, n) as if the axis were mirrored about each of its ends.
+func mirror(i, n int) int {
+	i = wrap(i, 2*n)
+	if i >= n {
+		i = 2*n - 1 - i
+	}
+	return i
+}
@@ -0,0 +1,98 @@
+package model
+
+import "testing"
+
+func TestBoundary_at(t *testing.T) {
+	cells := [][]uint{
+		{1, 2, 3},
+		{4, 5, 6},
+	}
+
+	type args struct {
+		x int
+		y int
+	}
+	tests := []struct {
+		name     string
+		boundary Boundary
+		args     args
+		want     uint
+		wantErr  bool
+	}{
+		{
+			name:     "void on grid",
+			boundary: Boundary{Mode: Void},
+			args:     args{x: 1, y: 2},
+			want:     6,
+			wantErr:  false,
+		},
+		{
+			name:     "void off grid",
+			boundary: Boundary{Mode: Void},
+			args:     args{x: -1, y: 0},
+			want:     0,
+			wantErr:  true,
+		},
+		{
+			name:     "toroidal left",
+			boundary: Boundary{Mode: Toroidal},
+			args:     args{x: -1, y: 0},
+			want:     4,
+			wantErr:  false,
+		},
+		{
+			name:     "toroidal top right",
+			boundary: Boundary{Mode: Toroidal},
+			args:     args{x: 2, y: 3},
+			want:     1,
+			wantErr:  false,
+		},
+		{
+			name:     "toroidal far away",
+			boundary: Boundary{Mode: Toroidal},
+			args:     args{x: -5, y: 7},
+			want:     5,
+			wantErr:  false,
+		},
+		{
+			name:     "reflective left",
+			boundary: Boundary{Mode: Reflective},
+			args:     args{x: -1, y: 1},
+			want:     2,
+			wantErr:  false,
+		},
+		{
+			name:     "reflective top",
+			boundary: Boundary{Mode: Reflective},
+			args:     args{x: 1, y: 4},
+			want:     5,
+			wantErr:  false,
+		},
+		{
+			name:     "constant off grid",
+			boundary: Boundary{Mode: Constant, OutsideState: 9},
+			args:     args{x: 2, y: -1},
+			want:     9,
+			wantErr:  false,
+		},
+		{
+			name:     "constant on grid",
+			boundary: Boundary{Mode: Constant, OutsideState: 9},
+			args:     args{x: 0, y: 0},
+			want:     1,
+			wantErr:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.boundary.at(cells, tt.args.x, tt.args.y)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Boundary.at() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Boundary.at() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,21 +2,28 @@ package model
 
 import (
 	"fmt"
-	"math"
+	"math/rand/v2"
 )
 
 // Cell is provided as a parameter to the [Predicate] required for [TransitionSet.AddTransition].
 // It exposes no fields, but it provides methods that allow the user to query the state of neighbouring cells.
 type Cell struct {
-	x, y  int
-	cells [][]uint
+	x, y     int
+	cells    [][]uint
+	boundary Boundary
+	topology Topology
+	graph    *Graph
+	rand     *cellRand
 }
 
 // Neighbour checks the state of the neighbouring cell with a provided displacement.
 // This function will return an error if a displacement of (0, 0) has been supplied, or there is no cell at that position (i.e. off the edge of the grid).
+// Whether a position off the edge of the grid has a cell depends on the [Boundary] of the automaton; see [Automaton.SetBoundary].
 //
 // Positive X goes right, positive Y goes up.
 //
+// On a [Graph], cells have no positions relative to each other, so this always returns an error. Use [Cell.Neighbours] instead.
+//
 //	n, err = c.Neighbour(-1, 0) // neighbour to the left of c
 //	n, err = c.Neighbour(1, -1) // neighbour to the bottom right of c
 func (c Cell) Neighbour(x, y int) (uint, error) {
@@ -24,7 +31,11 @@ func (c Cell) Neighbour(x, y int) (uint, error) {
 		return 0, fmt.Errorf("cannot query self as neighbour")
 	}
 
-	return at(c.cells, c.x+x, c.y+y)
+	if c.graph != nil {
+		return 0, fmt.Errorf("cannot query neighbours by displacement on a graph")
+	}
+
+	return c.boundary.at(c.cells, c.x+x, c.y+y)
 }
 
 // CountNeighbours computes the number of neighbouring cells that have a given target state.
@@ -33,26 +44,95 @@ func (c Cell) Neighbour(x, y int) (uint, error) {
 // If true, all eight surrounding cells are considered.
 // If false, only the four orthogonally adjacent cells are considered.
 //
-// If this cell is at the edge of the grid, it will have fewer neighbours.
-// Off-grid locations are considered to have no state, and will not contribute to the returned value.
+// If the automaton has a [Hexagonal] topology, moore is ignored and the six hexagonal neighbours are considered, as in [Cell.CountHexNeighbours].
+//
+// If the automaton is simulated on a [Graph], moore is ignored and the neighbours of this cell's node are considered, as listed by [Cell.Neighbours].
+//
+// If this cell is at the edge of the grid, it may have fewer neighbours.
+// Off-grid locations with no state under the automaton's [Boundary] will not contribute to the returned value.
 func (c Cell) CountNeighbours(target uint, moore bool) uint {
+	if c.graph != nil {
+		return c.countGraphNeighbours(target)
+	}
+	if c.topology == Hexagonal {
+		return c.CountHexNeighbours(target)
+	}
+	if moore {
+		return c.Count(target, mooreNeighbourhood)
+	}
+	return c.Count(target, vonNeumannNeighbourhood)
+}
+
+// State returns the current state of this cell.
+func (c Cell) State() uint {
+	return c.cells[c.x][c.y]
+}
+
+// Count computes the number of cells in the neighbourhood n that have a given target state.
+// If n contains the offset (0, 0), this cell itself is counted too.
+//
+// Larger than radius 1, or unusually shaped, neighbourhoods can be built with [MooreNeighbourhood], [VonNeumannNeighbourhood],
+// [CrossNeighbourhood], [CircularNeighbourhood] and [HexagonalNeighbourhood], or by listing offsets directly.
+// Neighbourhoods should be built once, outside of the [Predicate], rather than on every call.
+//
+// If the automaton has a [Hexagonal] topology, offsets are taken to be on the sheared lattice described in [HexagonalNeighbourhood],
+// and are converted to the offset rows of the topology, so that Count(target, HexagonalNeighbourhood(r)) counts every cell within r steps on the hexagonal grid.
+// The offset (X, Y) becomes the axial displacement (X + Y, -Y) passed to [Cell.HexNeighbour].
+//
+// As with [Cell.CountNeighbours], off-grid locations with no state under the automaton's [Boundary] will not contribute to the returned value.
+func (c Cell) Count(target uint, n Neighbourhood) uint {
 	count := uint(0)
-	for x := -1; x <= 1; x++ {
-		for y := -1; y <= 1; y++ {
-			diagonal := math.Abs(float64(x))+math.Abs(float64(y)) == 2
-			if !moore && diagonal {
-				continue
+	for _, o := range n {
+		var state uint
+		if o.X == 0 && o.Y == 0 {
+			state = c.State()
+		} else {
+			neighbour, err := c.Neighbour(o.X, o.Y)
+			if c.topology == Hexagonal {
+				neighbour, err = c.HexNeighbour(o.X+o.Y, -o.Y)
 			}
-
-			neighbour, err := c.Neighbour(x, y)
 			if err != nil {
 				continue
 			}
+			state = neighbour
+		}
 
-			if neighbour == target {
-				count++
-			}
+		if state == target {
+			count++
 		}
 	}
 	return count
 }
+
+// mooreMask describes which of the eight surrounding cells have a given target state, as a 3x3 bitmask in reading order.
+// Bit 0 is the top left neighbour, bit 4 is this cell (and is never set) and bit 8 is the bottom right neighbour.
+func (c Cell) mooreMask(target uint) uint {
+	mask := uint(0)
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			neighbour, err := c.Neighbour(col-1, 1-row)
+			if err != nil || neighbour != target {
+				continue
+			}
+
+			mask |= 1 << (row*3 + col)
+		}
+	}
+	return mask
+}
+
+// Rand returns a random number generator for this cell, for use in stochastic rules.
+//
+// The generator is seeded from the automaton's seed (see [Automaton.SetSeed]), the generation being computed, and the position of this cell.
+// This makes simulations reproducible: the same seed gives the same results, regardless of the number of workers or the order in which cells are computed.
+// Every predicate called for this cell in the same generation shares the same generator, so they draw different numbers from it.
+//
+// The generator must not be retained after the predicate returns.
+func (c Cell) Rand() *rand.Rand {
+	if c.rand == nil {
+		r := newCellRand()
+		r.reset(0, 0, c.x, c.y)
+		return r.get()
+	}
+	return c.rand.get()
+}
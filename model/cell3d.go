@@ -0,0 +1,101 @@
+package model
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// Cell3D is provided as a parameter to the [Predicate3D] required for [TransitionSet3D.AddTransition].
+// It is the three-dimensional counterpart to [Cell].
+type Cell3D struct {
+	x, y, z  int
+	cells    Grid3D
+	boundary Boundary
+	rand     *cellRand
+}
+
+// Neighbour checks the state of the neighbouring cell with a provided displacement.
+// This function will return an error if a displacement of (0, 0, 0) has been supplied, or there is no cell at that position (i.e. off the edge of the grid).
+// Whether a position off the edge of the grid has a cell depends on the [Boundary] of the automaton; see [Automaton3D.SetBoundary].
+//
+// Positive X goes right, positive Y goes up and positive Z goes to the next plane.
+func (c Cell3D) Neighbour(x, y, z int) (uint, error) {
+	if x == 0 && y == 0 && z == 0 {
+		return 0, fmt.Errorf("cannot query self as neighbour")
+	}
+
+	return c.boundary.at3D(c.cells, c.x+x, c.y+y, c.z+z)
+}
+
+// CountNeighbours computes the number of neighbouring cells that have a given target state.
+//
+// Neighbour is defined by the moore parameter.
+// If true, all 26 surrounding cells are considered.
+// If false, only the 6 cells sharing a face with this one are considered.
+//
+// Off-grid locations with no state under the automaton's [Boundary] will not contribute to the returned value.
+func (c Cell3D) CountNeighbours(target uint, moore bool) uint {
+	count := uint(0)
+	for dz := -1; dz <= 1; dz++ {
+		for dx := -1; dx <= 1; dx++ {
+			for dy := -1; dy <= 1; dy++ {
+				if dx == 0 && dy == 0 && dz == 0 {
+					continue
+				}
+				if !moore && abs(dx)+abs(dy)+abs(dz) != 1 {
+					continue
+				}
+
+				neighbour, err := c.Neighbour(dx, dy, dz)
+				if err == nil && neighbour == target {
+					count++
+				}
+			}
+		}
+	}
+	return count
+}
+
+// State returns the current state of this cell.
+func (c Cell3D) State() uint {
+	return c.cells[c.z][c.x][c.y]
+}
+
+// Rand returns a random number generator for this cell, for use in stochastic rules. It behaves as [Cell.Rand].
+func (c Cell3D) Rand() *rand.Rand {
+	if c.rand == nil {
+		r := newCellRand()
+		r.reset3D(0, 0, c.x, c.y, c.z)
+		return r.get()
+	}
+	return c.rand.get()
+}
+
+// Predicate3D is the three-dimensional counterpart to [Predicate].
+type Predicate3D func(cell Cell3D) bool
+
+type transition3D struct {
+	Predicate Predicate3D
+	NewState  uint
+}
+
+// TransitionSet3D is the three-dimensional counterpart to [TransitionSet].
+type TransitionSet3D [][]transition3D
+
+// NewTransitionSet3D creates a new [TransitionSet3D] for use with [NewAutomaton3D].
+// You should add transitions to it using [TransitionSet3D.AddTransition].
+func NewTransitionSet3D() TransitionSet3D {
+	return make(TransitionSet3D, 0)
+}
+
+// AddTransition adds a transition to this [TransitionSet3D]. It behaves as [TransitionSet.AddTransition].
+func (t *TransitionSet3D) AddTransition(fromState, toState uint, rule Predicate3D) {
+	for uint(len(*t)) <= max(fromState, toState) {
+		*t = append(*t, make([]transition3D, 0))
+	}
+
+	(*t)[fromState] = append((*t)[fromState], transition3D{
+		NewState:  toState,
+		Predicate: rule,
+	})
+}
@@ -0,0 +1,46 @@
+package model
+
+import "testing"
+
+func TestCell3D_CountNeighbours(t *testing.T) {
+	full := NewGrid3D(3, 3, 3, 1)
+
+	tests := []struct {
+		name     string
+		boundary Boundary
+		x, y, z  int
+		moore    bool
+		want     uint
+	}{
+		{name: "moore centre", x: 1, y: 1, z: 1, moore: true, want: 26},
+		{name: "von neumann centre", x: 1, y: 1, z: 1, moore: false, want: 6},
+		{name: "moore corner", x: 0, y: 0, z: 0, moore: true, want: 7},
+		{name: "von neumann corner", x: 0, y: 0, z: 0, moore: false, want: 3},
+		{name: "moore corner toroidal", boundary: Boundary{Mode: Toroidal}, x: 0, y: 0, z: 0, moore: true, want: 26},
+		{name: "von neumann edge constant", boundary: Boundary{Mode: Constant, OutsideState: 0}, x: 1, y: 0, z: 0, moore: false, want: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Cell3D{x: tt.x, y: tt.y, z: tt.z, cells: full, boundary: tt.boundary}
+			if got := c.CountNeighbours(1, tt.moore); got != tt.want {
+				t.Errorf("Cell3D.CountNeighbours() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCell3D_Neighbour(t *testing.T) {
+	g := NewGrid3D(2, 2, 2, 0)
+	g[1][0][1] = 4
+
+	c := Cell3D{cells: g}
+	if got, err := c.Neighbour(0, 1, 1); err != nil || got != 4 {
+		t.Errorf("Cell3D.Neighbour(0, 1, 1) = %v, %v, want 4, nil", got, err)
+	}
+	if _, err := c.Neighbour(0, 0, 0); err == nil {
+		t.Errorf("Cell3D.Neighbour(0, 0, 0) should return an error")
+	}
+	if _, err := c.Neighbour(0, 0, -1); err == nil {
+		t.Errorf("Cell3D.Neighbour(0, 0, -1) should return an error off grid")
+	}
+}
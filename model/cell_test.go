@@ -204,3 +204,79 @@ func TestCell_CountNeighbours(t *testing.T) {
 		})
 	}
 }
+
+func TestCell_Count(t *testing.T) {
+	cells := [][]uint{
+		{1, 1, 1, 1, 1},
+		{1, 0, 0, 0, 1},
+		{1, 0, 1, 0, 1},
+		{1, 0, 0, 0, 1},
+		{1, 1, 1, 1, 1},
+	}
+
+	type args struct {
+		target uint
+		n      Neighbourhood
+	}
+	tests := []struct {
+		name string
+		x, y int
+		args args
+		want uint
+	}{
+		{
+			name: "moore radius 1",
+			x:    2,
+			y:    2,
+			args: args{target: 1, n: MooreNeighbourhood(1)},
+			want: 0,
+		},
+		{
+			name: "moore radius 2",
+			x:    2,
+			y:    2,
+			args: args{target: 1, n: MooreNeighbourhood(2)},
+			want: 16,
+		},
+		{
+			name: "von neumann radius 2",
+			x:    2,
+			y:    2,
+			args: args{target: 1, n: VonNeumannNeighbourhood(2)},
+			want: 4,
+		},
+		{
+			name: "including self",
+			x:    2,
+			y:    2,
+			args: args{target: 1, n: append(MooreNeighbourhood(2), Offset{0, 0})},
+			want: 17,
+		},
+		{
+			name: "off grid",
+			x:    0,
+			y:    0,
+			args: args{target: 1, n: MooreNeighbourhood(2)},
+			want: 5,
+		},
+		{
+			name: "custom",
+			x:    2,
+			y:    2,
+			args: args{target: 0, n: Neighbourhood{{1, 0}, {2, 0}, {-1, 1}}},
+			want: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Cell{
+				x:     tt.x,
+				y:     tt.y,
+				cells: cells,
+			}
+			if got := c.Count(tt.args.target, tt.args.n); got != tt.want {
+				t.Errorf("Cell.Count() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package model
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestParseGenerationsRule(t *testing.T) {
+	tests := []struct {
+		name       string
+		rule       string
+		wantStates uint
+		wantErr    bool
+	}{
+		{name: "brian's brain", rule: "B2/S/3", wantStates: 3, wantErr: false},
+		{name: "golly states prefix", rule: "B2/S/C3", wantStates: 3, wantErr: false},
+		{name: "survival first", rule: "/2/3", wantStates: 3, wantErr: false},
+		{name: "star wars", rule: "345/2/4", wantStates: 4, wantErr: false},
+		{name: "life", rule: "B3/S23/2", wantStates: 2, wantErr: false},
+		{name: "hensel", rule: "B2-a/S/5", wantStates: 5, wantErr: false},
+		{name: "missing states", rule: "B2/S", wantErr: true},
+		{name: "one state", rule: "B2/S/1", wantErr: true},
+		{name: "bad states", rule: "B2/S/x", wantErr: true},
+		{name: "bad conditions", rule: "B9/S/3", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := ParseGenerationsRule(tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseGenerationsRule() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && a.CountStates() != tt.wantStates {
+				t.Errorf("ParseGenerationsRule().CountStates() = %v, want %v", a.CountStates(), tt.wantStates)
+			}
+		})
+	}
+}
+
+func TestParseGenerationsRule_Step(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+		c    [][]uint
+		want [][]uint
+	}{
+		{
+			name: "brian's brain",
+			rule: "B2/S/3",
+			c:    [][]uint{{1, 0, 0}, {0, 0, 0}, {1, 0, 2}},
+			// the two alive cells start dying, and the dying cell dies. Only the centre column has exactly two alive neighbours
+			want: [][]uint{{2, 0, 0}, {1, 1, 0}, {2, 0, 0}},
+		},
+		{
+			name: "dying cells are not alive",
+			rule: "B2/S/4",
+			c:    [][]uint{{2, 0, 0}, {0, 0, 0}, {3, 0, 1}},
+			want: [][]uint{{3, 0, 0}, {0, 0, 0}, {0, 0, 2}},
+		},
+		{
+			name: "survival",
+			rule: "B/S1/3",
+			c:    [][]uint{{1, 1, 0}, {0, 0, 0}, {0, 0, 1}},
+			want: [][]uint{{1, 1, 0}, {0, 0, 0}, {0, 0, 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := ParseGenerationsRule(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseGenerationsRule() error = %v", err)
+			}
+			if got := a.Step(tt.c); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseGenerationsRule(%q).Step() = %v, want %v", tt.rule, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGenerationsRule_Life(t *testing.T) {
+	c := NewGrid(19, 23, 0)
+	r := rand.New(rand.NewSource(3))
+	for x := range c {
+		for y := range c[x] {
+			c[x][y] = uint(r.Intn(2))
+		}
+	}
+
+	a, err := ParseGenerationsRule("23/3/2")
+	if err != nil {
+		t.Fatalf("ParseGenerationsRule() error = %v", err)
+	}
+	if got, want := a.Step(c), newTestConways().Step(c); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseGenerationsRule(%q).Step() differs from Conway's Game of Life", "23/3/2")
+	}
+}
+
+func TestParseGenerationsRule_Colouring(t *testing.T) {
+	a, err := ParseGenerationsRule("345/2/6")
+	if err != nil {
+		t.Fatalf("ParseGenerationsRule() error = %v", err)
+	}
+
+	colouring := a.GetColouring()
+	for state := 3; state < len(colouring); state++ {
+		if colouring[state].B >= colouring[state-1].B {
+			t.Errorf("colouring of state %v (%+v) is not dimmer than state %v (%+v)", state, colouring[state], state-1, colouring[state-1])
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package model
+
+import "fmt"
+
+// Graph is a network of nodes joined by edges, on which an [Automaton] can be simulated in place of a grid. You should use the [NewGraph] function to create one.
+//
+// Apply a graph to an automaton with [Automaton.SetGraph]. Each node is then a cell, and its neighbours are the nodes joined to it, rather than the cells around it.
+// The states of the nodes are held in a grid with one column per node and a height of 1, indexed as cells[node][0], which can be allocated with [Graph.NewGrid].
+type Graph struct {
+	// neighbours[n] lists the nodes that node n can see
+	neighbours [][]uint
+}
+
+// NewGraph constructs a graph of the given number of nodes, numbered from 0, with no edges.
+func NewGraph(nodes uint) *Graph {
+	return &Graph{neighbours: make([][]uint, nodes)}
+}
+
+// CountNodes returns the number of nodes in this graph.
+func (g *Graph) CountNodes() uint {
+	return uint(len(g.neighbours))
+}
+
+// AddEdge joins nodes a and b with an undirected edge, so each is a neighbour of the other.
+func (g *Graph) AddEdge(a, b uint) error {
+	if err := g.AddArc(a, b); err != nil {
+		return err
+	}
+	if a == b {
+		return nil
+	}
+	return g.AddArc(b, a)
+}
+
+// AddArc joins two nodes with a directed edge, so from is a neighbour of to, but not the other way around.
+// In other words, to is influenced by from.
+func (g *Graph) AddArc(from, to uint) error {
+	nodes := g.CountNodes()
+	if from >= nodes || to >= nodes {
+		return fmt.Errorf("edge %v -> %v invalid, there are only %v nodes (max = %v)", from, to, nodes, int(nodes)-1)
+	}
+
+	g.neighbours[to] = append(g.neighbours[to], from)
+	return nil
+}
+
+// Neighbours returns the nodes that node is influenced by. The returned slice must not be modified.
+func (g *Graph) Neighbours(node uint) []uint {
+	return g.neighbours[node]
+}
+
+// NewGrid allocates a grid to hold the state of every node in this graph, with every node set to state. See [Graph].
+func (g *Graph) NewGrid(state uint) [][]uint {
+	return NewGrid(g.CountNodes(), 1, state)
+}
+
+// Node returns the index of this cell's node on the automaton's [Graph]. Off a graph, it returns the index of the cell's column.
+func (c Cell) Node() uint {
+	return uint(c.x)
+}
+
+// Degree returns the number of neighbours of this cell's node on the automaton's [Graph]. Off a graph, it returns 0.
+func (c Cell) Degree() uint {
+	if c.graph == nil {
+		return 0
+	}
+	return uint(len(c.graph.neighbours[c.x]))
+}
+
+// Neighbours returns the states of the neighbours of this cell's node on the automaton's [Graph], in the order their edges were added.
+// Off a graph, it returns nil.
+//
+// A new slice is allocated on every call. To count neighbours in a given state, [Cell.CountNeighbours] is cheaper.
+func (c Cell) Neighbours() []uint {
+	if c.graph == nil {
+		return nil
+	}
+
+	neighbours := c.graph.neighbours[c.x]
+	states := make([]uint, len(neighbours))
+	for i, n := range neighbours {
+		states[i] = c.cells[n][0]
+	}
+	return states
+}
+
+// countGraphNeighbours computes the number of neighbours of this cell's node that have a given target state.
+func (c Cell) countGraphNeighbours(target uint) uint {
+	count := uint(0)
+	for _, n := range c.graph.neighbours[c.x] {
+		if c.cells[n][0] == target {
+			count++
+		}
+	}
+	return count
+}
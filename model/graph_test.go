@@ -0,0 +1,126 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGraph_AddEdge(t *testing.T) {
+	g := NewGraph(3)
+
+	if err := g.AddEdge(0, 1); err != nil {
+		t.Fatalf("Graph.AddEdge() error = %v", err)
+	}
+	if err := g.AddArc(2, 1); err != nil {
+		t.Fatalf("Graph.AddArc() error = %v", err)
+	}
+	if err := g.AddEdge(0, 3); err == nil {
+		t.Errorf("Graph.AddEdge() should reject a node that does not exist")
+	}
+	if err := g.AddArc(5, 0); err == nil {
+		t.Errorf("Graph.AddArc() should reject a node that does not exist")
+	}
+
+	tests := []struct {
+		node uint
+		want []uint
+	}{
+		{node: 0, want: []uint{1}},
+		{node: 1, want: []uint{0, 2}},
+		{node: 2, want: nil},
+	}
+	for _, tt := range tests {
+		if got := g.Neighbours(tt.node); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Graph.Neighbours(%v) = %v, want %v", tt.node, got, tt.want)
+		}
+	}
+}
+
+func TestCell_Graph(t *testing.T) {
+	g := NewGraph(4)
+	for _, edge := range [][2]uint{{0, 1}, {0, 2}, {0, 3}, {2, 3}} {
+		if err := g.AddEdge(edge[0], edge[1]); err != nil {
+			t.Fatalf("Graph.AddEdge() error = %v", err)
+		}
+	}
+
+	cells := g.NewGrid(0)
+	cells[1][0] = 1
+	cells[3][0] = 1
+
+	c := Cell{x: 0, cells: cells, graph: g}
+
+	if got := c.Node(); got != 0 {
+		t.Errorf("Cell.Node() = %v, want 0", got)
+	}
+	if got := c.Degree(); got != 3 {
+		t.Errorf("Cell.Degree() = %v, want 3", got)
+	}
+	if got, want := c.Neighbours(), []uint{1, 0, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Cell.Neighbours() = %v, want %v", got, want)
+	}
+	if got := c.CountNeighbours(1, false); got != 2 {
+		t.Errorf("Cell.CountNeighbours() = %v, want 2", got)
+	}
+	if _, err := c.Neighbour(1, 0); err == nil {
+		t.Errorf("Cell.Neighbour() should return an error on a graph")
+	}
+}
+
+func TestAutomaton_SetGraph(t *testing.T) {
+	const (
+		susceptible = iota
+		infected
+	)
+
+	transitions := NewTransitionSet()
+	transitions.AddTransition(susceptible, infected, func(cell Cell) bool {
+		return cell.CountNeighbours(infected, true) > 0
+	})
+	a, err := NewAutomaton(transitions, []Rgb{{}, {R: 1}})
+	if err != nil {
+		t.Fatalf("NewAutomaton() error = %v", err)
+	}
+
+	// a path 0 - 1 - 2 - 3, with a directed shortcut from 3 to 0
+	g := NewGraph(4)
+	for _, edge := range [][2]uint{{0, 1}, {1, 2}, {2, 3}} {
+		if err := g.AddEdge(edge[0], edge[1]); err != nil {
+			t.Fatalf("Graph.AddEdge() error = %v", err)
+		}
+	}
+	if err := g.AddArc(3, 0); err != nil {
+		t.Fatalf("Graph.AddArc() error = %v", err)
+	}
+
+	a.SetGraph(g)
+	if a.GetGraph() != g {
+		t.Errorf("Automaton.GetGraph() did not return the graph set")
+	}
+
+	cells := g.NewGrid(susceptible)
+	cells[0][0] = infected
+
+	cells = a.Step(cells)
+	if want := [][]uint{{1}, {1}, {0}, {0}}; !reflect.DeepEqual(cells, want) {
+		t.Errorf("Step() = %v, want %v", cells, want)
+	}
+
+	cells = a.Step(cells)
+	if want := [][]uint{{1}, {1}, {1}, {0}}; !reflect.DeepEqual(cells, want) {
+		t.Errorf("Step() = %v, want %v", cells, want)
+	}
+
+	// the shortcut only lets 3 influence 0, not the other way around
+	cells = a.Step([][]uint{{0}, {0}, {0}, {1}})
+	if want := [][]uint{{1}, {0}, {1}, {1}}; !reflect.DeepEqual(cells, want) {
+		t.Errorf("Step() = %v, want %v", cells, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Step() should panic if the grid does not match the graph")
+		}
+	}()
+	a.Step(NewGrid(4, 2, 0))
+}
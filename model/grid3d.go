@@ -0,0 +1,75 @@
+package model
+
+import "fmt"
+
+// Grid3D is a three-dimensional grid of cells, stored as a stack of two-dimensional planes and indexed as grid[z][x][y].
+// Each plane grid[z] is an ordinary grid, as used by [Automaton], so a single plane can be rendered or edited like any two-dimensional grid.
+type Grid3D [][][]uint
+
+// NewGrid3D allocates a width by height by depth grid of cells, with every cell set to state.
+//
+// As with [NewGrid], all cells share a single contiguous backing array.
+func NewGrid3D(width, height, depth, state uint) Grid3D {
+	flat := make([]uint, width*height*depth)
+	if state != 0 {
+		for i := range flat {
+			flat[i] = state
+		}
+	}
+
+	grid := make(Grid3D, depth)
+	for z := range grid {
+		grid[z] = make([][]uint, width)
+		for x := range grid[z] {
+			start := (uint(z)*width + uint(x)) * height
+			grid[z][x] = flat[start : start+height : start+height]
+		}
+	}
+
+	return grid
+}
+
+// Dimensions returns the width, height and depth of this grid.
+func (g Grid3D) Dimensions() (width, height, depth uint) {
+	depth = uint(len(g))
+	if depth > 0 {
+		width = uint(len(g[0]))
+	}
+	if width > 0 {
+		height = uint(len(g[0][0]))
+	}
+	return width, height, depth
+}
+
+// Plane returns the plane of cells at depth z, indexed as plane[x][y].
+// The plane shares memory with this grid, so changes to one are seen in the other.
+//
+// This is how a three-dimensional automaton is visualised with the two-dimensional renderer; see [github.com/michael-ryan/cellularautomata/v2.Config.Automaton3D].
+func (g Grid3D) Plane(z uint) [][]uint {
+	return g[z]
+}
+
+// at3D indexes the grid c, resolving off-grid locations according to the boundary b.
+// An error is only returned if the location is off-grid and the boundary does not give it a state.
+func (b Boundary) at3D(c Grid3D, x, y, z int) (uint, error) {
+	width, height, depth := c.Dimensions()
+	if width == 0 || height == 0 || depth == 0 {
+		return 0, fmt.Errorf("indexed empty grid")
+	}
+
+	w, h, d := int(width), int(height), int(depth)
+	if x >= 0 && x < w && y >= 0 && y < h && z >= 0 && z < d {
+		return c[z][x][y], nil
+	}
+
+	switch b.Mode {
+	case Toroidal:
+		return c[wrap(z, d)][wrap(x, w)][wrap(y, h)], nil
+	case Reflective:
+		return c[mirror(z, d)][mirror(x, w)][mirror(y, h)], nil
+	case Constant:
+		return b.OutsideState, nil
+	default:
+		return 0, fmt.Errorf("indexed off grid")
+	}
+}
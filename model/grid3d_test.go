@@ -0,0 +1,80 @@
+package model
+
+import "testing"
+
+func TestNewGrid3D(t *testing.T) {
+	g := NewGrid3D(2, 3, 4, 1)
+
+	if width, height, depth := g.Dimensions(); width != 2 || height != 3 || depth != 4 {
+		t.Fatalf("Dimensions() = %v, %v, %v, want 2, 3, 4", width, height, depth)
+	}
+
+	for z := range g {
+		for x := range g[z] {
+			for y := range g[z][x] {
+				if g[z][x][y] != 1 {
+					t.Fatalf("g[%v][%v][%v] = %v, want 1", z, x, y, g[z][x][y])
+				}
+			}
+		}
+	}
+
+	// columns must not overlap, even though they share a backing array
+	g[1][1] = append(g[1][1], 7)
+	if g[1][0][0] != 1 || g[2][0][0] != 1 {
+		t.Errorf("appending to a column overwrote its neighbour")
+	}
+}
+
+func TestGrid3D_Plane(t *testing.T) {
+	g := NewGrid3D(2, 2, 3, 0)
+
+	plane := g.Plane(1)
+	plane[1][0] = 5
+
+	if g[1][1][0] != 5 {
+		t.Errorf("Plane() does not share memory with the grid")
+	}
+	if g[0][1][0] != 0 || g[2][1][0] != 0 {
+		t.Errorf("Plane() wrote to the wrong plane")
+	}
+}
+
+func TestBoundary_at3D(t *testing.T) {
+	// each cell holds 100z + 10x + y, plus 1 so no cell is 0
+	g := NewGrid3D(2, 3, 4, 0)
+	for z := range g {
+		for x := range g[z] {
+			for y := range g[z][x] {
+				g[z][x][y] = uint(100*z+10*x+y) + 1
+			}
+		}
+	}
+
+	tests := []struct {
+		name     string
+		boundary Boundary
+		x, y, z  int
+		want     uint
+		wantErr  bool
+	}{
+		{name: "void on grid", boundary: Boundary{Mode: Void}, x: 1, y: 2, z: 3, want: 313},
+		{name: "void off grid", boundary: Boundary{Mode: Void}, x: 0, y: 0, z: -1, wantErr: true},
+		{name: "toroidal below", boundary: Boundary{Mode: Toroidal}, x: 0, y: 0, z: -1, want: 301},
+		{name: "toroidal above", boundary: Boundary{Mode: Toroidal}, x: 2, y: 3, z: 4, want: 1},
+		{name: "reflective below", boundary: Boundary{Mode: Reflective}, x: 1, y: 1, z: -1, want: 12},
+		{name: "constant", boundary: Boundary{Mode: Constant, OutsideState: 9}, x: 0, y: 0, z: 4, want: 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.boundary.at3D(g, tt.x, tt.y, tt.z)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Boundary.at3D() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Boundary.at3D() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
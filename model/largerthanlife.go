@@ -0,0 +1,219 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxLargerThanLifeRange is the largest neighbourhood radius accepted by [ParseLargerThanLifeRule], matching Golly.
+const maxLargerThanLifeRange = 500
+
+// largerThanLifeRule is a parsed Larger than Life rule. birth[n] and survival[n] report whether n alive cells in the neighbourhood cause a birth or allow survival.
+type largerThanLifeRule struct {
+	states        uint
+	middle        bool
+	neighbourhood Neighbourhood
+	birth         []bool
+	survival      []bool
+}
+
+// ParseLargerThanLifeRule constructs an [Automaton] from a Larger than Life (LtL) rule string, in the notation used by Golly for HROT rules:
+//
+//	R5,C0,M1,S34..58,B34..45,NM  // Bosco's Rule
+//
+// The parts are:
+//   - Rr: the radius r of the neighbourhood, from 1 to 500.
+//   - Cc: the number of states. C0 and C2 both give a two-state rule. With more states, alive cells that do not survive pass through dying states as in [ParseGenerationsRule].
+//   - Mm: M1 includes the cell itself in its count of alive neighbours, M0 does not.
+//   - Slist: the counts of alive neighbours that allow an alive cell to survive.
+//   - Blist: the counts of alive neighbours that cause a dead cell to become alive.
+//   - Nn: the shape of the neighbourhood, one of M (Moore), N (von Neumann), C (circular), + (cross) or H (hexagonal). See [Neighbourhood]. Defaults to Moore if omitted.
+//
+// Each list is a comma separated set of counts or ranges of counts, e.g. S2..3,5,7-9. Ranges may be written with ".." or "-".
+//
+// Counts are taken with [Cell.Count], so they honour the automaton's [Boundary].
+// A predicate examines every cell in the neighbourhood, so large radii are slow to simulate.
+//
+// [https://conwaylife.com/wiki/Larger_than_Life]
+func ParseLargerThanLifeRule(rule string) (*Automaton, error) {
+	r, err := parseLargerThanLifeRule(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	count := func(cell Cell) uint {
+		n := cell.Count(1, r.neighbourhood)
+		if r.middle && cell.State() == 1 {
+			n++
+		}
+		return n
+	}
+
+	return newGenerationsAutomaton(r.states, r.neighbourhood.Radius(), func(cell Cell) bool {
+		return r.birth[count(cell)]
+	}, func(cell Cell) bool {
+		return r.survival[count(cell)]
+	})
+}
+
+func parseLargerThanLifeRule(rule string) (largerThanLifeRule, error) {
+	r := largerThanLifeRule{states: 2}
+
+	var (
+		radius         = -1
+		shape          = byte('M')
+		list           *[][2]int
+		birth, survive [][2]int
+		seen           = make(map[byte]bool)
+	)
+
+	for _, token := range strings.Split(strings.TrimSpace(rule), ",") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			return r, fmt.Errorf("empty part in rule %q", rule)
+		}
+
+		// a token starting with a digit continues the previous list of counts
+		if token[0] >= '0' && token[0] <= '9' {
+			if list == nil {
+				return r, fmt.Errorf("unexpected count %q in rule %q, counts must follow S or B", token, rule)
+			}
+			bounds, err := parseCountRange(token)
+			if err != nil {
+				return r, fmt.Errorf("invalid count %q in rule %q: %w", token, rule, err)
+			}
+			*list = append(*list, bounds)
+			continue
+		}
+
+		key, value := token[0], token[1:]
+		if key >= 'a' && key <= 'z' {
+			key -= 'a' - 'A'
+		}
+		if seen[key] {
+			return r, fmt.Errorf("%c given more than once in rule %q", key, rule)
+		}
+		seen[key] = true
+		list = nil
+
+		switch key {
+		case 'R':
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > maxLargerThanLifeRange {
+				return r, fmt.Errorf("invalid range %q in rule %q, must be from 1 to %v", value, rule, maxLargerThanLifeRange)
+			}
+			radius = n
+		case 'C':
+			n, err := strconv.ParseUint(value, 10, 0)
+			if err != nil || n == 1 {
+				return r, fmt.Errorf("invalid number of states %q in rule %q, must be 0 or at least 2", value, rule)
+			}
+			r.states = max(uint(n), 2)
+		case 'M':
+			if value != "0" && value != "1" {
+				return r, fmt.Errorf("invalid middle %q in rule %q, must be 0 or 1", value, rule)
+			}
+			r.middle = value == "1"
+		case 'S', 'B':
+			list = &survive
+			if key == 'B' {
+				list = &birth
+			}
+			if value != "" {
+				bounds, err := parseCountRange(value)
+				if err != nil {
+					return r, fmt.Errorf("invalid count %q in rule %q: %w", value, rule, err)
+				}
+				*list = append(*list, bounds)
+			}
+		case 'N':
+			if len(value) != 1 || !strings.Contains("MNC+H", strings.ToUpper(value)) {
+				return r, fmt.Errorf("unsupported neighbourhood %q in rule %q, must be one of M, N, C, + or H", value, rule)
+			}
+			shape = strings.ToUpper(value)[0]
+		default:
+			return r, fmt.Errorf("unexpected part %q in rule %q", token, rule)
+		}
+	}
+
+	if radius < 0 {
+		return r, fmt.Errorf("rule %q must give a range with R", rule)
+	}
+	if !seen['S'] || !seen['B'] {
+		return r, fmt.Errorf("rule %q must give both survival (S) and birth (B) conditions", rule)
+	}
+
+	switch shape {
+	case 'M':
+		r.neighbourhood = MooreNeighbourhood(uint(radius))
+	case 'N':
+		r.neighbourhood = VonNeumannNeighbourhood(uint(radius))
+	case 'C':
+		r.neighbourhood = CircularNeighbourhood(uint(radius))
+	case '+':
+		r.neighbourhood = CrossNeighbourhood(uint(radius))
+	case 'H':
+		r.neighbourhood = HexagonalNeighbourhood(uint(radius))
+	}
+
+	maxCount := len(r.neighbourhood)
+	if r.middle {
+		maxCount++
+	}
+
+	var err error
+	if r.survival, err = countSet(survive, maxCount); err != nil {
+		return r, fmt.Errorf("invalid survival conditions in rule %q: %w", rule, err)
+	}
+	if r.birth, err = countSet(birth, maxCount); err != nil {
+		return r, fmt.Errorf("invalid birth conditions in rule %q: %w", rule, err)
+	}
+
+	return r, nil
+}
+
+// parseCountRange parses a single count, or a range of counts written as a..b or a-b.
+func parseCountRange(s string) ([2]int, error) {
+	low, high, isRange := strings.Cut(s, "..")
+	if !isRange {
+		low, high, isRange = strings.Cut(s, "-")
+	}
+	if !isRange {
+		high = low
+	}
+
+	a, err := strconv.Atoi(low)
+	if err != nil {
+		return [2]int{}, err
+	}
+	b, err := strconv.Atoi(high)
+	if err != nil {
+		return [2]int{}, err
+	}
+	if a < 0 {
+		return [2]int{}, fmt.Errorf("count %v is negative", a)
+	}
+	if a > b {
+		return [2]int{}, fmt.Errorf("range %v..%v is backwards", a, b)
+	}
+
+	return [2]int{a, b}, nil
+}
+
+// countSet converts ranges of counts into a lookup table indexed by count, from 0 to maxCount.
+func countSet(ranges [][2]int, maxCount int) ([]bool, error) {
+	set := make([]bool, maxCount+1)
+	for _, bounds := range ranges {
+		if bounds[0] < 0 {
+			return nil, fmt.Errorf("count %v is negative", bounds[0])
+		}
+		if bounds[1] > maxCount {
+			return nil, fmt.Errorf("count %v is larger than the neighbourhood, which has at most %v alive cells", bounds[1], maxCount)
+		}
+		for n := bounds[0]; n <= bounds[1]; n++ {
+			set[n] = true
+		}
+	}
+	return set, nil
+}
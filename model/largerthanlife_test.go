@@ -0,0 +1,105 @@
+package model
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestParseLargerThanLifeRule(t *testing.T) {
+	tests := []struct {
+		name       string
+		rule       string
+		wantStates uint
+		wantErr    bool
+	}{
+		{name: "bosco", rule: "R5,C0,M1,S34..58,B34..45,NM", wantStates: 2, wantErr: false},
+		{name: "lower case", rule: "r5,c0,m1,s34..58,b34..45,nm", wantStates: 2, wantErr: false},
+		{name: "no neighbourhood", rule: "R1,C0,M0,S2..3,B3", wantStates: 2, wantErr: false},
+		{name: "lists", rule: "R2,C2,S2-4,6,8..9,B3,7,NN", wantStates: 2, wantErr: false},
+		{name: "empty survival", rule: "R1,C0,M0,S,B2,NM", wantStates: 2, wantErr: false},
+		{name: "multi-state", rule: "R3,C5,M1,S10..20,B12..15,NC", wantStates: 5, wantErr: false},
+		{name: "cross", rule: "R2,C0,M0,S1,B2,N+", wantStates: 2, wantErr: false},
+		{name: "hexagonal", rule: "R2,C0,M0,S1,B2,NH", wantStates: 2, wantErr: false},
+		{name: "missing range", rule: "C0,M1,S34..58,B34..45,NM", wantErr: true},
+		{name: "range too large", rule: "R501,C0,M1,S34..58,B34..45,NM", wantErr: true},
+		{name: "one state", rule: "R1,C1,M0,S2..3,B3,NM", wantErr: true},
+		{name: "bad middle", rule: "R1,C0,M2,S2..3,B3,NM", wantErr: true},
+		{name: "count too large", rule: "R1,C0,M0,S2..9,B3,NM", wantErr: true},
+		{name: "backwards range", rule: "R1,C0,M0,S3..2,B3,NM", wantErr: true},
+		{name: "negative range", rule: "R1,C0,M0,S-3..5,B3", wantErr: true},
+		{name: "negative count", rule: "R1,C0,M0,S2..3,B-1", wantErr: true},
+		{name: "unknown neighbourhood", rule: "R1,C0,M0,S2..3,B3,NX", wantErr: true},
+		{name: "missing birth", rule: "R1,C0,M0,S2..3,NM", wantErr: true},
+		{name: "dangling count", rule: "3,R1,C0,M0,S2..3,B3", wantErr: true},
+		{name: "repeated part", rule: "R1,R2,C0,M0,S2..3,B3", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := ParseLargerThanLifeRule(tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseLargerThanLifeRule() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && a.CountStates() != tt.wantStates {
+				t.Errorf("ParseLargerThanLifeRule().CountStates() = %v, want %v", a.CountStates(), tt.wantStates)
+			}
+		})
+	}
+}
+
+func TestParseLargerThanLifeRule_Step(t *testing.T) {
+	c := NewGrid(30, 30, 0)
+	r := rand.New(rand.NewSource(4))
+	for x := range c {
+		for y := range c[x] {
+			c[x][y] = uint(r.Intn(2))
+		}
+	}
+
+	bosco := append(MooreNeighbourhood(5), Offset{0, 0})
+	ts := NewTransitionSet()
+	ts.AddTransition(0, 1, func(cell Cell) bool {
+		n := cell.Count(1, bosco)
+		return n >= 34 && n <= 45
+	})
+	ts.AddTransition(1, 0, func(cell Cell) bool {
+		n := cell.Count(1, bosco)
+		return n < 34 || n > 58
+	})
+	boscos, _ := NewAutomaton(ts, []Rgb{{R: 0, G: 0, B: 0}, {R: 1, G: 1, B: 1}})
+
+	tests := []struct {
+		name string
+		rule string
+		want *Automaton
+	}{
+		{name: "conway", rule: "R1,C0,M0,S2..3,B3,NM", want: newTestConways()},
+		{name: "conway with middle", rule: "R1,C0,M1,S3..4,B3,NM", want: newTestConways()},
+		{name: "bosco", rule: "R5,C0,M1,S34..58,B34..45,NM", want: boscos},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := ParseLargerThanLifeRule(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseLargerThanLifeRule() error = %v", err)
+			}
+			if got, want := a.Step(c), tt.want.Step(c); !reflect.DeepEqual(got, want) {
+				t.Errorf("ParseLargerThanLifeRule(%q).Step() differs from the hand-written rule", tt.rule)
+			}
+		})
+	}
+}
+
+func TestParseLargerThanLifeRule_Decay(t *testing.T) {
+	a, err := ParseLargerThanLifeRule("R1,C3,M0,S,B,NM")
+	if err != nil {
+		t.Fatalf("ParseLargerThanLifeRule() error = %v", err)
+	}
+
+	c := [][]uint{{1, 2}, {0, 0}}
+	want := [][]uint{{2, 0}, {0, 0}}
+	if got := a.Step(c); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseLargerThanLifeRule().Step() = %v, want %v", got, want)
+	}
+}
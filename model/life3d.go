@@ -0,0 +1,141 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// life3DRule is a parsed three-dimensional Life-like rule. birth[n] and survival[n] report whether n alive neighbours cause a birth or allow survival.
+type life3DRule struct {
+	states   uint
+	moore    bool
+	birth    []bool
+	survival []bool
+}
+
+// ParseLife3DRule constructs an [Automaton3D] from a three-dimensional Life-like rule string.
+//
+// State 0 is dead and state 1 is alive. The following notations are accepted:
+//
+//	4555                  // Bays' notation: survive with 4 to 5 neighbours, born with exactly 5
+//	4-5/5                 // survival/birth
+//	S4-5/B5               // survival/birth, with explicit prefixes in either order
+//	13-26/13-14,17-19/2/M // survival/birth/states/neighbourhood: Clouds 1
+//
+// Each list of conditions is a comma separated set of counts or ranges of counts, as in [ParseLargerThanLifeRule].
+// The optional number of states works as in [ParseGenerationsRule]: alive cells that do not survive pass through dying states before becoming dead.
+// The optional neighbourhood is M for the 26-cell Moore neighbourhood (the default) or N for the 6-cell von Neumann neighbourhood.
+//
+// Counts are taken with [Cell3D.CountNeighbours], so they honour the automaton's [Boundary].
+//
+// [https://softologyblog.wordpress.com/2019/12/28/3d-cellular-automata-3/]
+func ParseLife3DRule(rule string) (*Automaton3D, error) {
+	r, err := parseLife3DRule(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	const (
+		dead = iota
+		alive
+	)
+
+	dying := uint(2)
+	if r.states == 2 {
+		dying = dead
+	}
+
+	t := NewTransitionSet3D()
+	t.AddTransition(dead, alive, func(cell Cell3D) bool {
+		return r.birth[cell.CountNeighbours(alive, r.moore)]
+	})
+	t.AddTransition(alive, dying, func(cell Cell3D) bool {
+		return !r.survival[cell.CountNeighbours(alive, r.moore)]
+	})
+
+	always := func(cell Cell3D) bool { return true }
+	for state := uint(2); state < r.states; state++ {
+		t.AddTransition(state, (state+1)%r.states, always)
+	}
+
+	return NewAutomaton3D(t, generationsColouring(r.states))
+}
+
+func parseLife3DRule(rule string) (life3DRule, error) {
+	r := life3DRule{states: 2, moore: true}
+
+	trimmed := strings.TrimSpace(rule)
+
+	var survival, birth string
+	parts := strings.Split(trimmed, "/")
+	switch {
+	case len(parts) == 1 && len(trimmed) == 4 && strings.Trim(trimmed, "0123456789") == "":
+		// Bays' notation gives the bounds of the survival and birth ranges as single digits
+		survival = trimmed[0:1] + "-" + trimmed[1:2]
+		birth = trimmed[2:3] + "-" + trimmed[3:4]
+	case len(parts) >= 2 && len(parts) <= 4:
+		first, second := strings.ToUpper(parts[0]), strings.ToUpper(parts[1])
+		if strings.HasPrefix(first, "B") || strings.HasPrefix(second, "S") {
+			first, second = second, first
+		}
+		if strings.HasPrefix(first, "B") || strings.HasPrefix(second, "S") {
+			return r, fmt.Errorf("rule %q must give one survival (S) and one birth (B) condition", rule)
+		}
+		survival = strings.TrimPrefix(first, "S")
+		birth = strings.TrimPrefix(second, "B")
+
+		if len(parts) >= 3 {
+			count := strings.TrimPrefix(strings.ToUpper(parts[2]), "C")
+			states, err := strconv.ParseUint(count, 10, 0)
+			if err != nil || states < 2 {
+				return r, fmt.Errorf("invalid number of states %q in rule %q, must be at least 2", parts[2], rule)
+			}
+			r.states = uint(states)
+		}
+
+		if len(parts) == 4 {
+			switch strings.ToUpper(parts[3]) {
+			case "M":
+				r.moore = true
+			case "N":
+				r.moore = false
+			default:
+				return r, fmt.Errorf("unsupported neighbourhood %q in rule %q, must be M or N", parts[3], rule)
+			}
+		}
+	default:
+		return r, fmt.Errorf("rule %q must be of the form survival/birth, or four digits in Bays' notation", rule)
+	}
+
+	maxCount := 26
+	if !r.moore {
+		maxCount = 6
+	}
+
+	var err error
+	if r.survival, err = parseCountList(survival, maxCount); err != nil {
+		return r, fmt.Errorf("invalid survival conditions in rule %q: %w", rule, err)
+	}
+	if r.birth, err = parseCountList(birth, maxCount); err != nil {
+		return r, fmt.Errorf("invalid birth conditions in rule %q: %w", rule, err)
+	}
+
+	return r, nil
+}
+
+// parseCountList parses a comma separated list of counts and ranges of counts into a lookup table indexed by count, from 0 to maxCount.
+func parseCountList(list string, maxCount int) ([]bool, error) {
+	var ranges [][2]int
+	if list != "" {
+		for _, part := range strings.Split(list, ",") {
+			bounds, err := parseCountRange(strings.TrimSpace(part))
+			if err != nil {
+				return nil, fmt.Errorf("invalid count %q: %w", part, err)
+			}
+			ranges = append(ranges, bounds)
+		}
+	}
+
+	return countSet(ranges, maxCount)
+}
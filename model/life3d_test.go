@@ -0,0 +1,73 @@
+package model
+
+import "testing"
+
+func TestParseLife3DRule(t *testing.T) {
+	tests := []struct {
+		name       string
+		rule       string
+		wantStates uint
+		wantErr    bool
+	}{
+		{name: "bays", rule: "4555", wantStates: 2, wantErr: false},
+		{name: "survival/birth", rule: "4-5/5", wantStates: 2, wantErr: false},
+		{name: "prefixes", rule: "S4-5/B5", wantStates: 2, wantErr: false},
+		{name: "birth first", rule: "B5/S4-5", wantStates: 2, wantErr: false},
+		{name: "clouds", rule: "13-26/13-14,17-19/2/M", wantStates: 2, wantErr: false},
+		{name: "generations", rule: "4/4/5/M", wantStates: 5, wantErr: false},
+		{name: "von neumann", rule: "0-6/1,3/2/N", wantStates: 2, wantErr: false},
+		{name: "too many for von neumann", rule: "7/1/2/N", wantErr: true},
+		{name: "too many for moore", rule: "27/1", wantErr: true},
+		{name: "two births", rule: "B4/B5", wantErr: true},
+		{name: "one state", rule: "4/4/1", wantErr: true},
+		{name: "bad neighbourhood", rule: "4/4/2/H", wantErr: true},
+		{name: "three digits", rule: "455", wantErr: true},
+		{name: "bad count", rule: "4/x", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := ParseLife3DRule(tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseLife3DRule() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && a.CountStates() != tt.wantStates {
+				t.Errorf("ParseLife3DRule().CountStates() = %v, want %v", a.CountStates(), tt.wantStates)
+			}
+		})
+	}
+}
+
+func TestParseLife3DRule_Conditions(t *testing.T) {
+	bays, err := parseLife3DRule("4555")
+	if err != nil {
+		t.Fatalf("parseLife3DRule() error = %v", err)
+	}
+	clouds, err := parseLife3DRule("S13-26/B13-14,17-19")
+	if err != nil {
+		t.Fatalf("parseLife3DRule() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		set   []bool
+		count int
+		want  bool
+	}{
+		{name: "4555 survives with 4", set: bays.survival, count: 4, want: true},
+		{name: "4555 survives with 5", set: bays.survival, count: 5, want: true},
+		{name: "4555 dies with 6", set: bays.survival, count: 6, want: false},
+		{name: "4555 born with 5", set: bays.birth, count: 5, want: true},
+		{name: "4555 not born with 4", set: bays.birth, count: 4, want: false},
+		{name: "clouds survives with 26", set: clouds.survival, count: 26, want: true},
+		{name: "clouds born with 18", set: clouds.birth, count: 18, want: true},
+		{name: "clouds not born with 15", set: clouds.birth, count: 15, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.set[tt.count]; got != tt.want {
+				t.Errorf("condition for %v = %v, want %v", tt.count, got, tt.want)
+			}
+		})
+	}
+}
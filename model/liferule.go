@@ -0,0 +1,253 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// henselLetters lists, for each count of alive neighbours, the letters Hensel notation uses to tell apart the distinct arrangements of those neighbours.
+var henselLetters = [9]string{
+	"",
+	"ce",
+	"ceaikn",
+	"ceaiknjqry",
+	"ceaiknjqrytwz",
+	"ceaiknjqry",
+	"ceaikn",
+	"ce",
+	"",
+}
+
+// henselShapes gives one arrangement of alive neighbours for each letter in henselLetters, for counts up to 4.
+// Arrangements are 3x3 bitmasks in reading order (bit 0 is the top left, bit 4 is the cell itself, bit 8 is the bottom right).
+// The arrangements for counts above 4 are the complements of those below it.
+var henselShapes = [5][]uint{
+	{0},
+	{1, 2},
+	{5, 10, 3, 40, 33, 68},
+	{69, 42, 11, 7, 98, 13, 14, 70, 41, 97},
+	{325, 170, 15, 45, 99, 71, 106, 102, 43, 101, 105, 78, 108},
+}
+
+// henselClasses maps a count of alive neighbours and a Hensel letter to every neighbourhood bitmask in that class.
+// The empty letter maps to every neighbourhood with that count.
+var henselClasses = func() [9]map[byte][]uint {
+	var classes [9]map[byte][]uint
+
+	for count := range classes {
+		classes[count] = make(map[byte][]uint)
+
+		for i := range max(len(henselLetters[count]), 1) {
+			shape := henselShape(count, i)
+
+			seen := make(map[uint]bool)
+			for _, mask := range symmetries(shape) {
+				if !seen[mask] {
+					seen[mask] = true
+					if len(henselLetters[count]) > 0 {
+						letter := henselLetters[count][i]
+						classes[count][letter] = append(classes[count][letter], mask)
+					}
+					classes[count][0] = append(classes[count][0], mask)
+				}
+			}
+		}
+	}
+
+	return classes
+}()
+
+// henselShape returns the i-th representative arrangement for the given count of alive neighbours.
+func henselShape(count, i int) uint {
+	if count <= 4 {
+		return henselShapes[count][i]
+	}
+
+	const neighbours = 0b111101111
+	return ^henselShapes[8-count][i] & neighbours
+}
+
+// symmetries returns the eight rotations and reflections of a 3x3 neighbourhood bitmask.
+func symmetries(mask uint) []uint {
+	transform := func(mask uint, f func(row, col int) (int, int)) uint {
+		out := uint(0)
+		for bit := range 9 {
+			if mask&(1<<bit) != 0 {
+				row, col := f(bit/3, bit%3)
+				out |= 1 << (row*3 + col)
+			}
+		}
+		return out
+	}
+	rotate := func(row, col int) (int, int) { return col, 2 - row }
+	flip := func(row, col int) (int, int) { return row, 2 - col }
+
+	masks := make([]uint, 0, 8)
+	for range 4 {
+		masks = append(masks, mask, transform(mask, flip))
+		mask = transform(mask, rotate)
+	}
+	return masks
+}
+
+// lifeRule describes a two-state Life-like rule by the neighbourhood bitmasks that cause a birth, and those that allow survival.
+type lifeRule struct {
+	birth, survival [512]bool
+}
+
+// ParseLifeRule constructs a two-state [Automaton] from a Life-like rule string, with state 0 (dead, black) and state 1 (alive, white).
+//
+// The following notations are accepted:
+//
+//	B3/S23    // birth/survival
+//	S23/B3    // survival/birth
+//	23/3      // survival/birth, without letters
+//	B2-a/S12  // Hensel (isotropic non-totalistic) notation
+//
+// In Hensel notation, each count of alive neighbours may be followed by letters that restrict it to particular arrangements of those neighbours,
+// or by a minus sign and letters that exclude those arrangements.
+//
+// Neighbours are counted over the Moore neighbourhood, honouring the automaton's [Boundary].
+// Rules without Hensel letters are stepped by the fast path described in [NewLifeLikeAutomaton].
+//
+// [https://conwaylife.com/wiki/Rulestring]
+func ParseLifeRule(rule string) (*Automaton, error) {
+	r, err := parseLifeRule(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := newGenerationsAutomaton(2, 1, func(cell Cell) bool {
+		return r.birth[cell.mooreMask(1)]
+	}, func(cell Cell) bool {
+		return r.survival[cell.mooreMask(1)]
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// rules that only depend on the number of alive neighbours can use the bit packed fast path
+	if bits, ok := r.totalistic(); ok {
+		a.bits = bits
+	}
+
+	return a, nil
+}
+
+func parseLifeRule(rule string) (lifeRule, error) {
+	r := lifeRule{}
+
+	birth, survival, err := splitLifeRule(rule)
+	if err != nil {
+		return r, err
+	}
+
+	err = r.setConditions(rule, birth, survival)
+	return r, err
+}
+
+// setConditions parses the birth and survival conditions of rule into r.
+func (r *lifeRule) setConditions(rule, birth, survival string) error {
+	if err := parseHensel(birth, &r.birth); err != nil {
+		return fmt.Errorf("invalid birth conditions in rule %q: %w", rule, err)
+	}
+
+	if err := parseHensel(survival, &r.survival); err != nil {
+		return fmt.Errorf("invalid survival conditions in rule %q: %w", rule, err)
+	}
+
+	return nil
+}
+
+// splitLifeRule separates a rule string into its birth and survival conditions, with any B and S prefixes removed.
+func splitLifeRule(rule string) (birth, survival string, err error) {
+	rule = strings.TrimSpace(rule)
+
+	parts := strings.Split(rule, "/")
+	if len(parts) == 1 {
+		// B3S23 style, with no separator
+		upper := strings.ToUpper(rule)
+		i := strings.Index(upper, "S")
+		if !strings.HasPrefix(upper, "B") || i < 0 {
+			return "", "", fmt.Errorf("rule %q must have the form B.../S... or S.../B...", rule)
+		}
+		parts = []string{rule[:i], rule[i:]}
+	}
+
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("rule %q must have exactly two parts separated by '/'", rule)
+	}
+
+	first, second := parts[0], parts[1]
+	prefix := func(s string) byte {
+		if s == "" {
+			return 0
+		}
+		switch s[0] {
+		case 'B', 'b':
+			return 'B'
+		case 'S', 's':
+			return 'S'
+		}
+		return 0
+	}
+
+	switch {
+	case prefix(first) == 'B' && prefix(second) == 'S':
+		return first[1:], second[1:], nil
+	case prefix(first) == 'S' && prefix(second) == 'B':
+		return second[1:], first[1:], nil
+	case prefix(first) == 0 && prefix(second) == 0:
+		// S/B form, as used by Golly
+		return second, first, nil
+	}
+
+	return "", "", fmt.Errorf("rule %q must have the form B.../S..., S.../B... or survival/birth", rule)
+}
+
+// parseHensel parses a sequence of neighbour counts, each optionally followed by Hensel letters, setting the matching neighbourhood bitmasks in set.
+func parseHensel(conditions string, set *[512]bool) error {
+	for i := 0; i < len(conditions); {
+		c := conditions[i]
+		if c < '0' || c > '8' {
+			return fmt.Errorf("unexpected character %q, expected a neighbour count 0-8", c)
+		}
+		count := int(c - '0')
+		i++
+
+		exclude := i < len(conditions) && conditions[i] == '-'
+		if exclude {
+			i++
+		}
+
+		start := i
+		for i < len(conditions) && conditions[i] >= 'a' && conditions[i] <= 'z' {
+			i++
+		}
+		letters := conditions[start:i]
+
+		if exclude && letters == "" {
+			return fmt.Errorf("'-' after %v must be followed by at least one letter", count)
+		}
+
+		for j := range len(letters) {
+			if !strings.ContainsRune(henselLetters[count], rune(letters[j])) {
+				return fmt.Errorf("letter %q is not valid for %v neighbours (valid letters are %q)", letters[j], count, henselLetters[count])
+			}
+		}
+
+		if letters == "" || exclude {
+			for _, mask := range henselClasses[count][0] {
+				set[mask] = true
+			}
+		}
+
+		for j := range len(letters) {
+			for _, mask := range henselClasses[count][letters[j]] {
+				set[mask] = !exclude
+			}
+		}
+	}
+
+	return nil
+}
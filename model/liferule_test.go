@@ -0,0 +1,159 @@
+package model
+
+import (
+	"math/bits"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func Test_henselClasses(t *testing.T) {
+	// number of distinct arrangements of n neighbours, up to rotation and reflection
+	wantLetters := [9]int{1, 2, 6, 10, 13, 10, 6, 2, 1}
+
+	for count, classes := range henselClasses {
+		seen := make(map[uint]bool)
+		letters := 0
+		for letter, masks := range classes {
+			if letter == 0 {
+				continue
+			}
+			letters++
+			for _, mask := range masks {
+				if seen[mask] {
+					t.Errorf("mask %09b appears in more than one class for count %v", mask, count)
+				}
+				seen[mask] = true
+			}
+		}
+
+		all := classes[0]
+		for _, mask := range all {
+			if got := bits.OnesCount(mask); got != count || mask&(1<<4) != 0 {
+				t.Errorf("mask %09b listed under count %v", mask, count)
+			}
+		}
+
+		wantMasks := 1
+		for i := range count {
+			wantMasks = wantMasks * (8 - i) / (i + 1)
+		}
+		if len(all) != wantMasks {
+			t.Errorf("count %v has %v masks, want %v", count, len(all), wantMasks)
+		}
+
+		if len(henselLetters[count]) > 0 {
+			if letters != wantLetters[count] {
+				t.Errorf("count %v has %v letters, want %v", count, letters, wantLetters[count])
+			}
+			if len(seen) != wantMasks {
+				t.Errorf("letters for count %v cover %v masks, want %v", count, len(seen), wantMasks)
+			}
+		}
+	}
+}
+
+func TestParseLifeRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		wantErr bool
+	}{
+		{name: "conway", rule: "B3/S23", wantErr: false},
+		{name: "lower case", rule: "b36/s23", wantErr: false},
+		{name: "survival first", rule: "S23/B3", wantErr: false},
+		{name: "golly", rule: "23/3", wantErr: false},
+		{name: "no separator", rule: "B3S23", wantErr: false},
+		{name: "seeds", rule: "B2/S", wantErr: false},
+		{name: "hensel", rule: "B2-a/S12", wantErr: false},
+		{name: "hensel letters", rule: "B2cek3/S2-an3", wantErr: false},
+		{name: "empty", rule: "", wantErr: true},
+		{name: "missing part", rule: "B3", wantErr: true},
+		{name: "mixed prefixes", rule: "B3/23", wantErr: true},
+		{name: "count too high", rule: "B9/S23", wantErr: true},
+		{name: "invalid letter", rule: "B1a/S23", wantErr: true},
+		{name: "dangling minus", rule: "B2-/S23", wantErr: true},
+		{name: "too many parts", rule: "B3/S23/2", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := ParseLifeRule(tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseLifeRule() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && a.CountStates() != 2 {
+				t.Errorf("ParseLifeRule().CountStates() = %v, want %v", a.CountStates(), 2)
+			}
+		})
+	}
+}
+
+func TestParseLifeRule_Step(t *testing.T) {
+	c := NewGrid(31, 17, 0)
+	r := rand.New(rand.NewSource(2))
+	for x := range c {
+		for y := range c[x] {
+			c[x][y] = uint(r.Intn(2))
+		}
+	}
+
+	want := newTestConways().Step(c)
+
+	for _, rule := range []string{"B3/S23", "S23/B3", "23/3", "B3S23", "B3/S2ceaikn3"} {
+		t.Run(rule, func(t *testing.T) {
+			a, err := ParseLifeRule(rule)
+			if err != nil {
+				t.Fatalf("ParseLifeRule() error = %v", err)
+			}
+			if got := a.Step(c); !reflect.DeepEqual(got, want) {
+				t.Errorf("ParseLifeRule(%q).Step() differs from Conway's Game of Life", rule)
+			}
+		})
+	}
+}
+
+func TestParseLifeRule_Hensel(t *testing.T) {
+	tests := []struct {
+		name string
+		c    [][]uint
+		want uint
+	}{
+		{
+			name: "2a not born",
+			// north and north east alive
+			c:    [][]uint{{0, 0, 0}, {0, 0, 1}, {0, 0, 1}},
+			want: 0,
+		},
+		{
+			name: "2i born",
+			// north and south alive
+			c:    [][]uint{{0, 0, 0}, {1, 0, 1}, {0, 0, 0}},
+			want: 1,
+		},
+		{
+			name: "2n born",
+			// north west and south east alive
+			c:    [][]uint{{0, 0, 1}, {0, 0, 0}, {1, 0, 0}},
+			want: 1,
+		},
+		{
+			name: "3 not born",
+			c:    [][]uint{{0, 0, 1}, {0, 0, 1}, {1, 0, 0}},
+			want: 0,
+		},
+	}
+
+	a, err := ParseLifeRule("B2-a/S12")
+	if err != nil {
+		t.Fatalf("ParseLifeRule() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := a.Step(tt.c)[1][1]; got != tt.want {
+				t.Errorf("ParseLifeRule(%q).Step()[1][1] = %v, want %v", "B2-a/S12", got, tt.want)
+			}
+		})
+	}
+}
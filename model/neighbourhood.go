@@ -0,0 +1,94 @@
+package model
+
+// Offset is a displacement from a cell to one of its neighbours. Positive X goes right, positive Y goes up, as in [Cell.Neighbour].
+type Offset struct {
+	X, Y int
+}
+
+// Neighbourhood is a set of offsets describing which cells count as neighbours, for use with [Cell.Count].
+//
+// Constructors are provided for the common shapes, but any mask can be described by listing its offsets directly:
+//
+//	knight := model.Neighbourhood{{1, 2}, {2, 1}, {2, -1}, {1, -2}, {-1, -2}, {-2, -1}, {-2, 1}, {-1, 2}}
+//
+// An offset of (0, 0) refers to the cell itself.
+type Neighbourhood []Offset
+
+var (
+	mooreNeighbourhood      = MooreNeighbourhood(1)
+	vonNeumannNeighbourhood = VonNeumannNeighbourhood(1)
+)
+
+// MooreNeighbourhood returns every cell within a square of the given radius, i.e. whose X and Y offsets are both at most radius.
+// Radius 1 gives the usual eight surrounding cells.
+func MooreNeighbourhood(radius uint) Neighbourhood {
+	return neighbourhoodWhere(radius, func(x, y int) bool {
+		return true
+	})
+}
+
+// VonNeumannNeighbourhood returns every cell within a diamond of the given radius, i.e. whose Manhattan distance is at most radius.
+// Radius 1 gives the four orthogonally adjacent cells.
+func VonNeumannNeighbourhood(radius uint) Neighbourhood {
+	return neighbourhoodWhere(radius, func(x, y int) bool {
+		return abs(x)+abs(y) <= int(radius)
+	})
+}
+
+// CrossNeighbourhood returns every cell in the same row or column, up to radius cells away.
+func CrossNeighbourhood(radius uint) Neighbourhood {
+	return neighbourhoodWhere(radius, func(x, y int) bool {
+		return x == 0 || y == 0
+	})
+}
+
+// CircularNeighbourhood returns every cell whose centre lies within a Euclidean distance of radius + 1/2.
+func CircularNeighbourhood(radius uint) Neighbourhood {
+	r := int(radius)
+	return neighbourhoodWhere(radius, func(x, y int) bool {
+		// x² + y² <= (r + 1/2)², in integers
+		return x*x+y*y <= r*r+r
+	})
+}
+
+// HexagonalNeighbourhood returns every cell within a hexagon of the given radius, emulated on the square grid.
+//
+// The grid is treated as a hexagonal lattice sheared so that the top right and bottom left diagonals are not adjacent, as in Golly.
+// Radius 1 gives six neighbours: the four orthogonally adjacent cells, plus the top left and bottom right diagonals.
+//
+// On an automaton with a [Hexagonal] topology, [Cell.Count] converts these offsets to the offset rows of that topology, so the same neighbourhood works on both.
+func HexagonalNeighbourhood(radius uint) Neighbourhood {
+	return neighbourhoodWhere(radius, func(x, y int) bool {
+		return abs(x+y) <= int(radius)
+	})
+}
+
+// Radius returns the largest X or Y offset in this neighbourhood.
+func (n Neighbourhood) Radius() uint {
+	r := 0
+	for _, o := range n {
+		r = max(r, abs(o.X), abs(o.Y))
+	}
+	return uint(r)
+}
+
+// neighbourhoodWhere returns every non-zero offset within the square of the given radius that satisfies include.
+func neighbourhoodWhere(radius uint, include func(x, y int) bool) Neighbourhood {
+	r := int(radius)
+	n := Neighbourhood{}
+	for x := -r; x <= r; x++ {
+		for y := -r; y <= r; y++ {
+			if (x != 0 || y != 0) && include(x, y) {
+				n = append(n, Offset{X: x, Y: y})
+			}
+		}
+	}
+	return n
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
@@ -0,0 +1,57 @@
+package model
+
+import "testing"
+
+func TestNeighbourhoods(t *testing.T) {
+	tests := []struct {
+		name       string
+		n          Neighbourhood
+		wantSize   int
+		wantRadius uint
+	}{
+		{name: "moore 1", n: MooreNeighbourhood(1), wantSize: 8, wantRadius: 1},
+		{name: "moore 3", n: MooreNeighbourhood(3), wantSize: 48, wantRadius: 3},
+		{name: "von neumann 1", n: VonNeumannNeighbourhood(1), wantSize: 4, wantRadius: 1},
+		{name: "von neumann 2", n: VonNeumannNeighbourhood(2), wantSize: 12, wantRadius: 2},
+		{name: "cross 2", n: CrossNeighbourhood(2), wantSize: 8, wantRadius: 2},
+		{name: "circular 1", n: CircularNeighbourhood(1), wantSize: 8, wantRadius: 1},
+		{name: "circular 2", n: CircularNeighbourhood(2), wantSize: 20, wantRadius: 2},
+		{name: "circular 5", n: CircularNeighbourhood(5), wantSize: 96, wantRadius: 5},
+		{name: "hexagonal 1", n: HexagonalNeighbourhood(1), wantSize: 6, wantRadius: 1},
+		{name: "hexagonal 2", n: HexagonalNeighbourhood(2), wantSize: 18, wantRadius: 2},
+		{name: "zero", n: MooreNeighbourhood(0), wantSize: 0, wantRadius: 0},
+		{name: "custom", n: Neighbourhood{{1, 2}, {-2, 1}}, wantSize: 2, wantRadius: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := len(tt.n); got != tt.wantSize {
+				t.Errorf("len() = %v, want %v", got, tt.wantSize)
+			}
+			if got := tt.n.Radius(); got != tt.wantRadius {
+				t.Errorf("Neighbourhood.Radius() = %v, want %v", got, tt.wantRadius)
+			}
+
+			seen := make(map[Offset]bool)
+			for _, o := range tt.n {
+				if seen[o] {
+					t.Errorf("offset %v listed twice", o)
+				}
+				if o.X == 0 && o.Y == 0 {
+					t.Errorf("neighbourhood includes the cell itself")
+				}
+				seen[o] = true
+			}
+		})
+	}
+}
+
+func TestHexagonalNeighbourhood(t *testing.T) {
+	n := HexagonalNeighbourhood(1)
+	for _, excluded := range []Offset{{1, 1}, {-1, -1}} {
+		for _, o := range n {
+			if o == excluded {
+				t.Errorf("HexagonalNeighbourhood(1) includes %v", excluded)
+			}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// One-dimensional automata are simulated on a grid with a height of 1, i.e. a row of cells indexed as cells[x][0].
+// Their neighbours are to the left and right, and off-grid locations are treated according to the automaton's [Boundary],
+// counting as state 0 if the boundary gives them no state.
+
+// NewElementaryAutomaton constructs a two-state, one-dimensional [Automaton] from a Wolfram rule number, such as rule 30 or rule 110.
+//
+// Each cell's next state is decided by its own state and the states of its left and right neighbours.
+// Reading those three states as a binary number n (left as the most significant bit), the cell's next state is bit n of rule.
+//
+// The automaton should be simulated on a grid with a height of 1. Use [github.com/michael-ryan/cellularautomata/v2.Config.Spacetime] to render it as a spacetime diagram.
+//
+// [https://mathworld.wolfram.com/ElementaryCellularAutomaton.html]
+func NewElementaryAutomaton(rule uint8) (*Automaton, error) {
+	t := NewTransitionSet()
+	for from := uint(0); from < 2; from++ {
+		to := 1 - from
+		t.AddTransition(from, to, func(cell Cell) bool {
+			n := cell.row(-1)<<2 | cell.State()<<1 | cell.row(1)
+			return uint(rule>>n)&1 == to
+		})
+	}
+
+	colouring := []Rgb{
+		{R: 1, G: 1, B: 1},
+		{R: 0, G: 0, B: 0},
+	}
+
+	a, err := NewAutomaton(t, colouring)
+	if err != nil {
+		return nil, err
+	}
+
+	a.SetLocality(1)
+	return a, nil
+}
+
+// NewTotalisticAutomaton1D constructs a one-dimensional [Automaton] with the given number of states, following a totalistic rule with the given Wolfram code.
+//
+// Each cell's next state is decided by the sum of the states of every cell within radius cells of it, itself included.
+// Writing code in base states, the cell's next state is the digit in the position given by that sum, where position 0 is the least significant digit.
+// For example, with 3 states and radius 1, code 1599 gives Wolfram's rule 1599.
+//
+// code must have no more digits in base states than there are possible sums, i.e. (states - 1) * (2 * radius + 1) + 1.
+// The states are coloured on a gradient from white (state 0) to black.
+//
+// [https://mathworld.wolfram.com/TotalisticCellularAutomaton.html]
+func NewTotalisticAutomaton1D(states, radius uint, code uint64) (*Automaton, error) {
+	if states < 2 {
+		return nil, fmt.Errorf("a totalistic automaton needs at least 2 states, got %v", states)
+	}
+	if radius < 1 {
+		return nil, fmt.Errorf("a totalistic automaton needs a radius of at least 1")
+	}
+
+	sums := (states-1)*(2*radius+1) + 1
+
+	// digits[n] is the next state for a sum of n
+	digits := make([]uint, sums)
+	remaining := code
+	for n := range digits {
+		digits[n] = uint(remaining % uint64(states))
+		remaining /= uint64(states)
+	}
+	if remaining != 0 {
+		return nil, fmt.Errorf("code %v has more digits in base %v than there are possible sums (%v)", code, states, sums)
+	}
+
+	r := int(radius)
+	sum := func(cell Cell) uint {
+		total := uint(0)
+		for dx := -r; dx <= r; dx++ {
+			total += cell.row(dx)
+		}
+		return total
+	}
+
+	t := NewTransitionSet()
+	for from := range states {
+		for to := range states {
+			if from == to {
+				continue
+			}
+			t.AddTransition(from, to, func(cell Cell) bool {
+				return digits[sum(cell)] == to
+			})
+		}
+	}
+
+	colouring := make([]Rgb, states)
+	for state := range colouring {
+		v := 1 - float64(state)/float64(states-1)
+		colouring[state] = Rgb{R: v, G: v, B: v}
+	}
+
+	a, err := NewAutomaton(t, colouring)
+	if err != nil {
+		return nil, err
+	}
+
+	a.SetLocality(radius)
+	return a, nil
+}
+
+// ParseWolframRule is a convenience wrapper around [NewElementaryAutomaton], accepting rule strings such as "Rule 30", "rule110", "W90" or simply "184".
+func ParseWolframRule(rule string) (*Automaton, error) {
+	number := strings.ToLower(strings.TrimSpace(rule))
+	for _, prefix := range []string{"rule", "w"} {
+		if strings.HasPrefix(number, prefix) {
+			number = strings.TrimSpace(strings.TrimPrefix(number, prefix))
+			break
+		}
+	}
+
+	n, err := strconv.ParseUint(number, 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Wolfram rule %q, must be of the form \"Rule n\" with n from 0 to 255", rule)
+	}
+
+	return NewElementaryAutomaton(uint8(n))
+}
+
+// row returns the state of the cell dx cells to the right of this one, or 0 if there is no cell there.
+func (c Cell) row(dx int) uint {
+	if dx == 0 {
+		return c.State()
+	}
+
+	state, err := c.Neighbour(dx, 0)
+	if err != nil {
+		return 0
+	}
+	return state
+}
@@ -0,0 +1,182 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newRow builds a one-dimensional grid from a list of states, one per cell from left to right.
+func newRow(states ...uint) [][]uint {
+	row := NewGrid(uint(len(states)), 1, 0)
+	for x, state := range states {
+		row[x][0] = state
+	}
+	return row
+}
+
+func TestNewElementaryAutomaton(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  uint8
+		c     [][]uint
+		steps int
+		want  [][]uint
+	}{
+		{
+			name:  "rule 30",
+			rule:  30,
+			c:     newRow(0, 0, 0, 1, 0, 0, 0),
+			steps: 3,
+			want:  newRow(1, 1, 0, 1, 1, 1, 1),
+		},
+		{
+			name:  "rule 110",
+			rule:  110,
+			c:     newRow(0, 0, 0, 0, 0, 0, 1),
+			steps: 3,
+			want:  newRow(0, 0, 0, 1, 1, 0, 1),
+		},
+		{
+			name:  "rule 90",
+			rule:  90,
+			c:     newRow(0, 0, 0, 1, 0, 0, 0),
+			steps: 2,
+			want:  newRow(0, 1, 0, 0, 0, 1, 0),
+		},
+		{
+			name:  "rule 0",
+			rule:  0,
+			c:     newRow(1, 1, 0, 1),
+			steps: 1,
+			want:  newRow(0, 0, 0, 0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewElementaryAutomaton(tt.rule)
+			if err != nil {
+				t.Fatalf("NewElementaryAutomaton() error = %v", err)
+			}
+
+			got := tt.c
+			for range tt.steps {
+				got = a.Step(got)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Step() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewElementaryAutomaton_Toroidal(t *testing.T) {
+	a, err := NewElementaryAutomaton(90)
+	if err != nil {
+		t.Fatalf("NewElementaryAutomaton() error = %v", err)
+	}
+	if err := a.SetBoundary(Boundary{Mode: Toroidal}); err != nil {
+		t.Fatalf("SetBoundary() error = %v", err)
+	}
+
+	// the left edge sees the right edge as its neighbour, and vice versa
+	got := a.Step(newRow(1, 0, 0, 0, 0))
+	want := newRow(0, 1, 0, 0, 1)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Step() = %v, want %v", got, want)
+	}
+}
+
+func TestNewTotalisticAutomaton1D(t *testing.T) {
+	tests := []struct {
+		name    string
+		states  uint
+		radius  uint
+		code    uint64
+		c       [][]uint
+		steps   int
+		want    [][]uint
+		wantErr bool
+	}{
+		{
+			name:   "rule 1599",
+			states: 3,
+			radius: 1,
+			code:   1599,
+			c:      newRow(0, 0, 1, 0, 0),
+			steps:  2,
+			want:   newRow(0, 1, 2, 1, 0),
+		},
+		{
+			name:   "two states matches elementary rule 150",
+			states: 2,
+			radius: 1,
+			// digits 1010 in base 2: alive when 1 or 3 cells are alive
+			code:  10,
+			c:     newRow(0, 0, 0, 1, 0, 0, 0),
+			steps: 2,
+			want:  newRow(0, 1, 0, 1, 0, 1, 0),
+		},
+		{
+			name:   "radius 2",
+			states: 2,
+			radius: 2,
+			// alive when exactly 1 cell within 2 is alive
+			code:  2,
+			c:     newRow(0, 0, 0, 1, 0, 0, 0),
+			steps: 1,
+			want:  newRow(0, 1, 1, 1, 1, 1, 0),
+		},
+		{name: "largest code", states: 2, radius: 1, code: 15},
+		{name: "code too large", states: 2, radius: 1, code: 16, wantErr: true},
+		{name: "one state", states: 1, radius: 1, wantErr: true},
+		{name: "zero radius", states: 2, radius: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewTotalisticAutomaton1D(tt.states, tt.radius, tt.code)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewTotalisticAutomaton1D() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil || tt.c == nil {
+				return
+			}
+
+			if a.CountStates() != tt.states {
+				t.Errorf("CountStates() = %v, want %v", a.CountStates(), tt.states)
+			}
+
+			got := tt.c
+			for range tt.steps {
+				got = a.Step(got)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Step() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWolframRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		wantErr bool
+	}{
+		{name: "rule with space", rule: "Rule 30", wantErr: false},
+		{name: "lower case", rule: "rule110", wantErr: false},
+		{name: "w prefix", rule: "W90", wantErr: false},
+		{name: "number only", rule: "184", wantErr: false},
+		{name: "too large", rule: "Rule 256", wantErr: true},
+		{name: "negative", rule: "Rule -1", wantErr: true},
+		{name: "missing number", rule: "Rule", wantErr: true},
+		{name: "unknown prefix", rule: "B30", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseWolframRule(tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseWolframRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package model
+
+import "math/rand/v2"
+
+// cellRand lazily seeds a random number generator for a single cell in a single generation.
+// Each worker owns one, and reuses it for every cell it computes, so that no allocation is needed unless [Cell.Rand] is actually called.
+type cellRand struct {
+	pcg    *rand.PCG
+	rand   *rand.Rand
+	seeded bool
+	seed1  uint64
+	seed2  uint64
+}
+
+func newCellRand() *cellRand {
+	pcg := rand.NewPCG(0, 0)
+	return &cellRand{
+		pcg:  pcg,
+		rand: rand.New(pcg),
+	}
+}
+
+// reset prepares r for the cell at (x, y) in the given generation. The generator is only seeded once it is first used.
+func (r *cellRand) reset(seed uint64, generation uint, x, y int) {
+	r.seeded = false
+	r.seed1 = splitMix64(seed ^ splitMix64(uint64(generation)))
+	r.seed2 = splitMix64(r.seed1 ^ uint64(uint32(x))<<32 ^ uint64(uint32(y)))
+}
+
+func (r *cellRand) get() *rand.Rand {
+	if !r.seeded {
+		r.pcg.Seed(r.seed1, r.seed2)
+		r.seeded = true
+	}
+	return r.rand
+}
+
+// splitMix64 scrambles the bits of x, so that nearby inputs give unrelated outputs.
+//
+// [https://prng.di.unimi.it/splitmix64.c]
+func splitMix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// reset3D prepares r for the cell at (x, y, z) in the given generation.
+func (r *cellRand) reset3D(seed uint64, generation uint, x, y, z int) {
+	r.reset(seed, generation, x, y)
+	r.seed2 = splitMix64(r.seed2 ^ uint64(uint32(z)))
+}
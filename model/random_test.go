@@ -0,0 +1,99 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func newTestStochastic() *Automaton {
+	t := NewTransitionSet()
+	t.AddTransition(0, 1, func(cell Cell) bool { return cell.Rand().Float64() < 0.5 })
+	t.AddTransition(0, 1, func(cell Cell) bool { return cell.Rand().Float64() < 0.5 })
+	t.AddTransition(1, 0, func(cell Cell) bool { return cell.Rand().IntN(3) == 0 })
+
+	a, _ := NewAutomaton(t, []Rgb{{R: 0, G: 0, B: 0}, {R: 1, G: 1, B: 1}})
+	return a
+}
+
+func TestCell_Rand_Reproducible(t *testing.T) {
+	src := NewGrid(40, 30, 0)
+
+	a := newTestStochastic()
+	a.SetSeed(42)
+	a.SetWorkers(1)
+	want := NewGrid(40, 30, 0)
+	a.StepWith(src, want, StepOptions{Generation: 7})
+
+	for _, workers := range []uint{2, 5, 0} {
+		t.Run(fmt.Sprintf("%v workers", workers), func(t *testing.T) {
+			b := newTestStochastic()
+			b.SetSeed(42)
+			b.SetWorkers(workers)
+			got := NewGrid(40, 30, 0)
+			b.StepWith(src, got, StepOptions{Generation: 7})
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Automaton.StepWith() with seed 42 and %v workers differs from 1 worker", workers)
+			}
+		})
+	}
+}
+
+func TestCell_Rand_Varies(t *testing.T) {
+	src := NewGrid(40, 30, 0)
+
+	step := func(seed uint64, generation uint) [][]uint {
+		a := newTestStochastic()
+		a.SetSeed(seed)
+		dst := NewGrid(40, 30, 0)
+		a.StepWith(src, dst, StepOptions{Generation: generation})
+		return dst
+	}
+
+	base := step(1, 0)
+	if reflect.DeepEqual(base, step(2, 0)) {
+		t.Errorf("different seeds gave identical steps")
+	}
+	if reflect.DeepEqual(base, step(1, 1)) {
+		t.Errorf("different generations gave identical steps")
+	}
+
+	// cells should not all make the same choice
+	alive := 0
+	for x := range base {
+		for y := range base[x] {
+			alive += int(base[x][y])
+		}
+	}
+	// each dead cell has two chances of 0.5, so roughly three quarters should be born
+	if total := 40 * 30; alive < total/2 || alive == total {
+		t.Errorf("%v of %v cells were born, want roughly three quarters", alive, total)
+	}
+}
+
+func TestAutomaton_Step_Generation(t *testing.T) {
+	a := newTestStochastic()
+	a.SetSeed(3)
+	src := NewGrid(40, 30, 0)
+
+	// Step and StepInto step from generation 0
+	want := NewGrid(40, 30, 0)
+	a.StepWith(src, want, StepOptions{Generation: 0})
+	if got := a.Step(src); !reflect.DeepEqual(got, want) {
+		t.Errorf("Automaton.Step() differs from Automaton.StepWith() from generation 0")
+	}
+
+	got := NewGrid(40, 30, 0)
+	a.StepInto(src, got)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Automaton.StepInto() differs from Automaton.StepWith() from generation 0")
+	}
+}
+
+func TestAutomaton_SetSeed(t *testing.T) {
+	a := newTestStochastic()
+	a.SetSeed(1234)
+	if got := a.GetSeed(); got != 1234 {
+		t.Errorf("Automaton.GetSeed() = %v, want %v", got, 1234)
+	}
+}
@@ -0,0 +1,82 @@
+package model
+
+import "fmt"
+
+// Topology describes how the cells of an [Automaton]'s grid are arranged, and so which cells neighbour each other.
+type Topology uint
+
+const (
+	// Square arranges cells in a square lattice, where each cell has eight neighbours: four sharing an edge and four sharing a corner.
+	// This is the default.
+	Square Topology = iota
+	// Hexagonal arranges cells in a hexagonal lattice, where each cell has six neighbours.
+	//
+	// The grid is still indexed as grid[x][y], with each y being a row of cells. Odd rows are offset by half a cell to the right,
+	// so the rows interlock like bricks. Neighbours are found with [Cell.HexNeighbour], which takes displacements in axial coordinates.
+	Hexagonal
+)
+
+// hexDirections lists the axial displacements of the six neighbours of a hexagonal cell, anticlockwise from the right.
+var hexDirections = [6]Offset{
+	{X: 1, Y: 0},
+	{X: 1, Y: -1},
+	{X: 0, Y: -1},
+	{X: -1, Y: 0},
+	{X: -1, Y: 1},
+	{X: 0, Y: 1},
+}
+
+// HexNeighbour checks the state of a cell on a hexagonal grid, displaced from this one by q and r in axial coordinates.
+// This function will return an error if a displacement of (0, 0) has been supplied, or there is no cell at that position, as for [Cell.Neighbour].
+//
+// Axial coordinates have two axes 120 degrees apart. Positive q goes right, and positive r goes down and to the right.
+// The six neighbours of a cell are at:
+//
+//	(1, 0)   // right
+//	(1, -1)  // up and to the right
+//	(0, -1)  // up and to the left
+//	(-1, 0)  // left
+//	(-1, 1)  // down and to the left
+//	(0, 1)   // down and to the right
+//
+// The grid is treated as hexagonal, with odd rows offset to the right (see [Hexagonal]), whatever the automaton's [Topology].
+// With a [Toroidal] boundary, the grid should have an even number of rows so that the offsets line up when it wraps.
+//
+// [https://www.redblobgames.com/grids/hexagons/#coordinates-axial]
+func (c Cell) HexNeighbour(q, r int) (uint, error) {
+	if q == 0 && r == 0 {
+		return 0, fmt.Errorf("cannot query self as neighbour")
+	}
+
+	if c.graph != nil {
+		return 0, fmt.Errorf("cannot query neighbours by displacement on a graph")
+	}
+
+	// rows are stored bottom up, so moving down a row decreases y
+	x, y := hexToOffset(q, r, c.y)
+	return c.boundary.at(c.cells, c.x+x, y)
+}
+
+// CountHexNeighbours computes the number of the six neighbouring cells on a hexagonal grid that have a given target state.
+//
+// As with [Cell.CountNeighbours], off-grid locations with no state under the automaton's [Boundary] will not contribute to the returned value.
+func (c Cell) CountHexNeighbours(target uint) uint {
+	count := uint(0)
+	for _, d := range hexDirections {
+		neighbour, err := c.HexNeighbour(d.X, d.Y)
+		if err == nil && neighbour == target {
+			count++
+		}
+	}
+	return count
+}
+
+// hexToOffset converts an axial displacement (q, r), from a cell in row y, into a displacement in x and the destination row on a grid with odd rows offset to the right.
+func hexToOffset(q, r, y int) (int, int) {
+	// r counts rows downwards, but y counts them upwards
+	toY := y - r
+
+	// each row down moves half a cell left in axial coordinates, which the offset of odd rows makes up for
+	dx := q + (r+(y&1)-(toY&1))/2
+	return dx, toY
+}
@@ -0,0 +1,142 @@
+package model
+
+import "testing"
+
+func TestCell_HexNeighbour(t *testing.T) {
+	// each cell holds 10x + y + 1, so no cell is 0
+	cells := NewGrid(4, 4, 0)
+	for x := range cells {
+		for y := range cells[x] {
+			cells[x][y] = uint(10*x+y) + 1
+		}
+	}
+
+	type args struct {
+		q int
+		r int
+	}
+	tests := []struct {
+		name    string
+		x, y    int
+		args    args
+		want    uint
+		wantErr bool
+	}{
+		{name: "odd row right", x: 1, y: 1, args: args{q: 1, r: 0}, want: 22},
+		{name: "odd row up right", x: 1, y: 1, args: args{q: 1, r: -1}, want: 23},
+		{name: "odd row up left", x: 1, y: 1, args: args{q: 0, r: -1}, want: 13},
+		{name: "odd row left", x: 1, y: 1, args: args{q: -1, r: 0}, want: 2},
+		{name: "odd row down left", x: 1, y: 1, args: args{q: -1, r: 1}, want: 11},
+		{name: "odd row down right", x: 1, y: 1, args: args{q: 0, r: 1}, want: 21},
+		{name: "even row up right", x: 1, y: 2, args: args{q: 1, r: -1}, want: 14},
+		{name: "even row up left", x: 1, y: 2, args: args{q: 0, r: -1}, want: 4},
+		{name: "even row down left", x: 1, y: 2, args: args{q: -1, r: 1}, want: 2},
+		{name: "even row down right", x: 1, y: 2, args: args{q: 0, r: 1}, want: 12},
+		{name: "two rows down", x: 1, y: 2, args: args{q: 0, r: 2}, want: 21},
+		{name: "self", x: 1, y: 1, args: args{q: 0, r: 0}, wantErr: true},
+		{name: "off grid", x: 0, y: 2, args: args{q: 0, r: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Cell{x: tt.x, y: tt.y, cells: cells}
+			got, err := c.HexNeighbour(tt.args.q, tt.args.r)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Cell.HexNeighbour() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Cell.HexNeighbour() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCell_CountNeighbours_Hexagonal(t *testing.T) {
+	full := NewGrid(4, 4, 1)
+
+	tests := []struct {
+		name     string
+		topology Topology
+		boundary Boundary
+		x, y     int
+		want     uint
+	}{
+		{name: "square centre", topology: Square, x: 1, y: 1, want: 8},
+		{name: "hexagonal centre", topology: Hexagonal, x: 1, y: 1, want: 6},
+		{name: "hexagonal even row corner", topology: Hexagonal, x: 0, y: 0, want: 2},
+		{name: "hexagonal odd row edge", topology: Hexagonal, x: 3, y: 1, want: 3},
+		{name: "hexagonal toroidal corner", topology: Hexagonal, boundary: Boundary{Mode: Toroidal}, x: 0, y: 0, want: 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Cell{x: tt.x, y: tt.y, cells: full, boundary: tt.boundary, topology: tt.topology}
+			if got := c.CountNeighbours(1, true); got != tt.want {
+				t.Errorf("Cell.CountNeighbours() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCell_Count_Hexagonal(t *testing.T) {
+	// a fixed scattering of alive cells
+	cells := NewGrid(12, 12, 0)
+	for x := range cells {
+		for y := range cells[x] {
+			cells[x][y] = uint((x*7+y*13)%5) % 2
+		}
+	}
+
+	for _, radius := range []int{1, 2, 3} {
+		n := HexagonalNeighbourhood(uint(radius))
+		for x := 3; x < 9; x++ {
+			for y := 3; y < 9; y++ {
+				c := Cell{x: x, y: y, cells: cells, topology: Hexagonal}
+
+				// every cell within radius steps on the hexagonal grid, in axial coordinates
+				want := uint(0)
+				for q := -radius; q <= radius; q++ {
+					for r := -radius; r <= radius; r++ {
+						if abs(q+r) > radius || (q == 0 && r == 0) {
+							continue
+						}
+						if state, err := c.HexNeighbour(q, r); err == nil && state == 1 {
+							want++
+						}
+					}
+				}
+
+				if got := c.Count(1, n); got != want {
+					t.Errorf("Cell{%v, %v}.Count(1, HexagonalNeighbourhood(%v)) = %v, want %v", x, y, radius, got, want)
+				}
+				if radius == 1 && c.Count(1, n) != c.CountHexNeighbours(1) {
+					t.Errorf("Cell{%v, %v}.Count(1, HexagonalNeighbourhood(1)) differs from Cell.CountHexNeighbours()", x, y)
+				}
+			}
+		}
+	}
+}
+
+func TestAutomaton_SetTopology(t *testing.T) {
+	a := newTestConways()
+
+	if err := a.SetTopology(Hexagonal); err != nil {
+		t.Fatalf("Automaton.SetTopology() error = %v", err)
+	}
+	if got := a.GetTopology(); got != Hexagonal {
+		t.Errorf("Automaton.GetTopology() = %v, want %v", got, Hexagonal)
+	}
+
+	if err := a.SetTopology(Hexagonal + 1); err == nil {
+		t.Errorf("Automaton.SetTopology() should reject an unknown topology")
+	}
+
+	// three hexagonal neighbours are enough to give birth, where a square lattice would count four
+	cells := NewGrid(3, 3, 0)
+	cells[0][1] = 1
+	cells[2][1] = 1
+	cells[1][2] = 1
+	cells[0][2] = 1
+	if got := a.Step(cells)[1][1]; got != 1 {
+		t.Errorf("Step() on a hexagonal topology gave centre state %v, want 1", got)
+	}
+}
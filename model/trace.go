@@ -0,0 +1,52 @@
+package model
+
+// Trace records which transitions fired during a single step, to help debug the order of rules in a [TransitionSet].
+// Pass one to [Automaton.StepWith] through [StepOptions]. It is reset at the start of every step.
+//
+// Rules are identified by the state they transition from and their index among the transitions from that state,
+// i.e. the order in which they were added with [TransitionSet.AddTransition].
+type Trace struct {
+	// Cells enables recording which rule decided the fate of every individual cell, in Rules.
+	// It is off by default, since it needs memory proportional to the size of the grid.
+	Cells bool
+	// Fired[fromState][ruleIndex] is the number of cells that rule fired for.
+	Fired [][]uint
+	// Rules[x][y] is the index of the rule that fired for the cell at (x, y), among the transitions from the state that cell was in, or -1 if no rule fired.
+	// It is only populated if Cells is true, and is nil otherwise.
+	Rules [][]int
+}
+
+// reset prepares the trace for a step of the given transition set, over a width by height grid.
+func (t *Trace) reset(transitions TransitionSet, width, height uint) {
+	t.Fired = newFiredTable(transitions)
+
+	if !t.Cells {
+		t.Rules = nil
+		return
+	}
+
+	if uint(len(t.Rules)) != width || (width > 0 && uint(len(t.Rules[0])) != height) {
+		t.Rules = make([][]int, width)
+		for x := range t.Rules {
+			t.Rules[x] = make([]int, height)
+		}
+	}
+}
+
+// merge adds the counts in fired to this trace.
+func (t *Trace) merge(fired [][]uint) {
+	for from := range fired {
+		for rule, count := range fired[from] {
+			t.Fired[from][rule] += count
+		}
+	}
+}
+
+// newFiredTable allocates a zeroed table with one count per rule in transitions.
+func newFiredTable(transitions TransitionSet) [][]uint {
+	fired := make([][]uint, len(transitions))
+	for from := range transitions {
+		fired[from] = make([]uint, len(transitions[from]))
+	}
+	return fired
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAutomaton_StepWith_Trace(t *testing.T) {
+	const (
+		off = iota
+		on
+	)
+
+	// a deliberately shadowed rule: the second transition from off can never fire, since the first always does for the same cells
+	ts := NewTransitionSet()
+	ts.AddTransition(off, on, func(cell Cell) bool { return cell.CountNeighbours(on, false) > 0 })
+	ts.AddTransition(off, on, func(cell Cell) bool { return cell.CountNeighbours(on, false) > 1 })
+	ts.AddTransition(on, off, func(cell Cell) bool { return true })
+
+	a, err := NewAutomaton(ts, []Rgb{{R: 0, G: 0, B: 0}, {R: 1, G: 1, B: 1}})
+	if err != nil {
+		t.Fatalf("NewAutomaton() error = %v", err)
+	}
+
+	src := [][]uint{
+		{0, 0, 0},
+		{0, 1, 0},
+		{0, 0, 0},
+	}
+
+	tests := []struct {
+		name      string
+		trace     *Trace
+		wantFired [][]uint
+		wantRules [][]int
+	}{
+		{
+			name:      "counts only",
+			trace:     &Trace{},
+			wantFired: [][]uint{{4, 0}, {1}},
+			wantRules: nil,
+		},
+		{
+			name:      "cells",
+			trace:     &Trace{Cells: true},
+			wantFired: [][]uint{{4, 0}, {1}},
+			wantRules: [][]int{
+				{-1, 0, -1},
+				{0, 0, 0},
+				{-1, 0, -1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, workers := range []uint{1, 2, 3} {
+				a.SetWorkers(workers)
+				dst := NewGrid(3, 3, 0)
+
+				// step twice, to check the trace is reset rather than accumulated
+				a.StepWith(src, dst, StepOptions{Trace: tt.trace})
+				a.StepWith(src, dst, StepOptions{Trace: tt.trace})
+
+				if !reflect.DeepEqual(tt.trace.Fired, tt.wantFired) {
+					t.Errorf("Trace.Fired with %v workers = %v, want %v", workers, tt.trace.Fired, tt.wantFired)
+				}
+				if !reflect.DeepEqual(tt.trace.Rules, tt.wantRules) {
+					t.Errorf("Trace.Rules with %v workers = %v, want %v", workers, tt.trace.Rules, tt.wantRules)
+				}
+			}
+		})
+	}
+}
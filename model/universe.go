@@ -0,0 +1,282 @@
+package model
+
+import (
+	"fmt"
+	"sync"
+)
+
+// universeChunk is the width and height, in cells, of the square chunks a [Universe] is stored in.
+const universeChunk = 64
+
+// chunkKey identifies a chunk of a [Universe] by its position, in chunks, from the origin.
+type chunkKey struct {
+	x, y int
+}
+
+// Universe is an unbounded grid of cells, which grows as the pattern on it does. You should use the [NewUniverse] function to create one.
+//
+// Every cell starts in a background state. Only square chunks of cells holding something other than the background are stored,
+// in a hash map, so a pattern can wander arbitrarily far from the origin. Each step evaluates the stored chunks and the chunks within the automaton's locality radius of them,
+// then drops any chunk that has returned entirely to the background.
+//
+// Cells are addressed by x and y coordinates, with positive x going right and positive y going up, as in a grid indexed as cells[x][y].
+// The automaton's [Boundary] is never used, since a universe has no edges.
+type Universe struct {
+	automaton  *Automaton
+	background uint
+	radius     int
+	chunks     map[chunkKey][][]uint
+	generation uint
+}
+
+// NewUniverse constructs an empty Universe for automaton, with every cell in the background state, at generation 0.
+//
+// The automaton must declare how far its predicates can see with [Automaton.SetLocality], as the rule constructors in this package do,
+// since each chunk is evaluated with only that many cells of its neighbours around it.
+// The background must be stable, i.e. a background cell surrounded by background cells must stay in the background, or the universe would fill up at once.
+// Universes do not support automata on a [Graph].
+func NewUniverse(automaton *Automaton, background uint) (*Universe, error) {
+	if automaton == nil {
+		return nil, fmt.Errorf("automaton must not be nil")
+	}
+
+	if automaton.graph != nil {
+		return nil, fmt.Errorf("an automaton on a graph cannot be simulated in a universe")
+	}
+
+	if background >= automaton.states {
+		return nil, fmt.Errorf("background state %v invalid, there are only %v states defined (max = %v)", background, automaton.states, automaton.states-1)
+	}
+
+	if !automaton.local {
+		return nil, fmt.Errorf("automaton must declare its locality with SetLocality to be simulated in a universe")
+	}
+
+	radius := int(automaton.reach())
+	// keep the padding around each chunk even, so rows keep their parity on a hexagonal topology
+	radius += radius % 2
+
+	u := &Universe{
+		automaton:  automaton,
+		background: background,
+		radius:     radius,
+		chunks:     make(map[chunkKey][][]uint),
+	}
+
+	// evaluate a cell of an empty universe, to check the background stays put
+	empty := NewGrid(uint(2*radius+1), uint(2*radius+1), background)
+	rng := newCellRand()
+	rng.reset(automaton.seed, 0, 0, 0)
+	if state, _ := automaton.next(empty, radius, radius, rng); state != background {
+		return nil, fmt.Errorf("background state %v is not stable, a cell surrounded by it becomes state %v", background, state)
+	}
+
+	return u, nil
+}
+
+// Generation returns the number of generations simulated so far.
+func (u *Universe) Generation() uint {
+	return u.generation
+}
+
+// CountChunks returns the number of chunks stored, each of which holds 64 by 64 cells.
+func (u *Universe) CountChunks() int {
+	return len(u.chunks)
+}
+
+// chunkOf returns the chunk holding the cell at (x, y), and the position of the cell within it.
+func chunkOf(x, y int) (chunkKey, int, int) {
+	key := chunkKey{x: floorDiv(x, universeChunk), y: floorDiv(y, universeChunk)}
+	return key, x - key.x*universeChunk, y - key.y*universeChunk
+}
+
+// floorDiv divides a by b, rounding towards negative infinity.
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// Get returns the state of the cell at (x, y).
+func (u *Universe) Get(x, y int) uint {
+	key, cx, cy := chunkOf(x, y)
+	chunk, ok := u.chunks[key]
+	if !ok {
+		return u.background
+	}
+	return chunk[cx][cy]
+}
+
+// Set sets the state of the cell at (x, y). It returns an error if state is not a state of the automaton.
+func (u *Universe) Set(x, y int, state uint) error {
+	if state >= u.automaton.states {
+		return fmt.Errorf("state %v invalid, there are only %v states defined (max = %v)", state, u.automaton.states, u.automaton.states-1)
+	}
+
+	key, cx, cy := chunkOf(x, y)
+	chunk, ok := u.chunks[key]
+	if !ok {
+		if state == u.background {
+			return nil
+		}
+		chunk = NewGrid(universeChunk, universeChunk, u.background)
+		u.chunks[key] = chunk
+	}
+
+	chunk[cx][cy] = state
+	return nil
+}
+
+// SetRegion copies cells, indexed as cells[x][y], into the universe with cells[0][0] at (x, y).
+// It returns an error, without copying anything, if any cell is not a state of the automaton.
+func (u *Universe) SetRegion(cells [][]uint, x, y int) error {
+	for i := range cells {
+		for j, state := range cells[i] {
+			if state >= u.automaton.states {
+				return fmt.Errorf("cell (%v, %v) has invalid state %v, there are only %v states defined (max = %v)", i, j, state, u.automaton.states, u.automaton.states-1)
+			}
+		}
+	}
+
+	for i := range cells {
+		for j, state := range cells[i] {
+			u.Set(x+i, y+j, state)
+		}
+	}
+	return nil
+}
+
+// Region copies the width by height block of cells with its bottom left corner at (x, y) into a new grid, indexed as cells[x][y].
+func (u *Universe) Region(x, y int, width, height uint) [][]uint {
+	cells := NewGrid(width, height, 0)
+	u.copyRegion(cells, x, y)
+	return cells
+}
+
+// copyRegion fills cells, whose bottom left corner is at (x, y), with the states of the universe, one chunk at a time.
+func (u *Universe) copyRegion(cells [][]uint, x, y int) {
+	width, height := len(cells), 0
+	if width > 0 {
+		height = len(cells[0])
+	}
+	if width == 0 || height == 0 {
+		return
+	}
+
+	for i := range cells {
+		for j := range cells[i] {
+			cells[i][j] = u.background
+		}
+	}
+
+	first, _, _ := chunkOf(x, y)
+	last, _, _ := chunkOf(x+width-1, y+height-1)
+	for kx := first.x; kx <= last.x; kx++ {
+		for ky := first.y; ky <= last.y; ky++ {
+			chunk, ok := u.chunks[chunkKey{x: kx, y: ky}]
+			if !ok {
+				continue
+			}
+
+			// the overlap of the chunk and the region, in universe coordinates
+			left, bottom := max(kx*universeChunk, x), max(ky*universeChunk, y)
+			right, top := min((kx+1)*universeChunk, x+width), min((ky+1)*universeChunk, y+height)
+			for gx := left; gx < right; gx++ {
+				copy(cells[gx-x][bottom-y:top-y], chunk[gx-kx*universeChunk][bottom-ky*universeChunk:top-ky*universeChunk])
+			}
+		}
+	}
+}
+
+// Bounds returns the smallest rectangle containing every cell that is not in the background state, as the coordinates of its bottom left corner and its size.
+// ok is false if every cell is in the background state.
+func (u *Universe) Bounds() (x, y int, width, height uint, ok bool) {
+	var minX, minY, maxX, maxY int
+	for key, chunk := range u.chunks {
+		for cx := range chunk {
+			for cy, state := range chunk[cx] {
+				if state == u.background {
+					continue
+				}
+
+				gx, gy := key.x*universeChunk+cx, key.y*universeChunk+cy
+				if !ok {
+					minX, minY, maxX, maxY, ok = gx, gy, gx, gy, true
+					continue
+				}
+				minX, maxX = min(minX, gx), max(maxX, gx)
+				minY, maxY = min(minY, gy), max(maxY, gy)
+			}
+		}
+	}
+
+	if !ok {
+		return 0, 0, 0, 0, false
+	}
+	return minX, minY, uint(maxX - minX + 1), uint(maxY - minY + 1), true
+}
+
+// Step advances the universe by a single generation, growing it wherever the pattern reaches a new chunk.
+func (u *Universe) Step() {
+	// every stored chunk, and every chunk within the locality radius of one, could change
+	reach := (u.radius + universeChunk - 1) / universeChunk
+	candidates := make(map[chunkKey]bool, len(u.chunks)*2)
+	for key := range u.chunks {
+		for dx := -reach; dx <= reach; dx++ {
+			for dy := -reach; dy <= reach; dy++ {
+				candidates[chunkKey{x: key.x + dx, y: key.y + dy}] = true
+			}
+		}
+	}
+
+	keys := make([]chunkKey, 0, len(candidates))
+	for key := range candidates {
+		keys = append(keys, key)
+	}
+
+	next := make(map[chunkKey][][]uint, len(keys))
+	mu := sync.Mutex{}
+	wg := sync.WaitGroup{}
+
+	workers := min(countWorkers(u.automaton.workers), max(len(keys), 1))
+	for w := range workers {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			size := universeChunk + 2*u.radius
+			padded := NewGrid(uint(size), uint(size), u.background)
+			rng := newCellRand()
+
+			for i := w; i < len(keys); i += workers {
+				key := keys[i]
+				left, bottom := key.x*universeChunk-u.radius, key.y*universeChunk-u.radius
+				u.copyRegion(padded, left, bottom)
+
+				chunk := NewGrid(universeChunk, universeChunk, u.background)
+				empty := true
+				for cx := range universeChunk {
+					for cy := range universeChunk {
+						px, py := cx+u.radius, cy+u.radius
+						rng.reset(u.automaton.seed, u.generation, left+px, bottom+py)
+						state, _ := u.automaton.next(padded, px, py, rng)
+						chunk[cx][cy] = state
+						empty = empty && state == u.background
+					}
+				}
+
+				if !empty {
+					mu.Lock()
+					next[key] = chunk
+					mu.Unlock()
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	u.chunks = next
+	u.generation++
+}
@@ -0,0 +1,233 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// newLocalConways returns Conway's Game of Life, declaring its locality so it can be simulated in a universe.
+func newLocalConways() *Automaton {
+	a := newTestConways()
+	a.SetLocality(1)
+	return a
+}
+
+func TestNewUniverse(t *testing.T) {
+	unstable, err := ParseLifeRule("B0/S8")
+	if err != nil {
+		t.Fatalf("ParseLifeRule() error = %v", err)
+	}
+
+	onGraph := newLocalConways()
+	onGraph.SetGraph(NewGraph(4))
+
+	tests := []struct {
+		name       string
+		automaton  *Automaton
+		background uint
+		wantErr    bool
+	}{
+		{"conways", newLocalConways(), 0, false},
+		{"no locality", newTestConways(), 0, true},
+		{"nil automaton", nil, 0, true},
+		{"invalid background", newLocalConways(), 2, true},
+		{"unstable background", unstable, 0, true},
+		{"graph", onGraph, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewUniverse(tt.automaton, tt.background)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewUniverse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUniverse_SetGet(t *testing.T) {
+	u, err := NewUniverse(newLocalConways(), 0)
+	if err != nil {
+		t.Fatalf("NewUniverse() error = %v", err)
+	}
+
+	points := [][2]int{{0, 0}, {-1, -1}, {63, 64}, {-64, 5}, {1000, -1000}}
+	for _, p := range points {
+		if err := u.Set(p[0], p[1], 1); err != nil {
+			t.Fatalf("Set(%v, %v) error = %v", p[0], p[1], err)
+		}
+	}
+	for _, p := range points {
+		if got := u.Get(p[0], p[1]); got != 1 {
+			t.Errorf("Get(%v, %v) = %v, want 1", p[0], p[1], got)
+		}
+	}
+	if got := u.Get(1, 0); got != 0 {
+		t.Errorf("Get(1, 0) = %v, want 0", got)
+	}
+	if got := u.CountChunks(); got != len(points) {
+		t.Errorf("CountChunks() = %v, want %v", got, len(points))
+	}
+
+	if err := u.Set(0, 0, 2); err == nil {
+		t.Errorf("Set() with an invalid state should return an error")
+	}
+
+	x, y, width, height, ok := u.Bounds()
+	if !ok || x != -64 || y != -1000 || width != 1065 || height != 1065 {
+		t.Errorf("Bounds() = (%v, %v, %v, %v, %v), want (-64, -1000, 1065, 1065, true)", x, y, width, height, ok)
+	}
+
+	// setting an empty chunk to the background should not allocate it
+	u.Set(500, 500, 0)
+	if got := u.CountChunks(); got != len(points) {
+		t.Errorf("CountChunks() = %v after setting the background, want %v", got, len(points))
+	}
+}
+
+func TestUniverse_Region(t *testing.T) {
+	u, err := NewUniverse(newLocalConways(), 0)
+	if err != nil {
+		t.Fatalf("NewUniverse() error = %v", err)
+	}
+
+	pattern := NewGrid(100, 70, 0)
+	for x := range pattern {
+		for y := range pattern[x] {
+			pattern[x][y] = uint((x*7 + y*3) % 5 % 2)
+		}
+	}
+	if err := u.SetRegion(pattern, -30, -10); err != nil {
+		t.Fatalf("SetRegion() error = %v", err)
+	}
+
+	if got := u.Region(-30, -10, 100, 70); !reflect.DeepEqual(got, pattern) {
+		t.Errorf("Region() did not return the pattern set by SetRegion()")
+	}
+
+	// a region overlapping the pattern's top right corner, and empty space beyond it
+	got := u.Region(60, 50, 20, 30)
+	for x := range got {
+		for y := range got[x] {
+			want := uint(0)
+			if px, py := x+90, y+60; px < 100 && py < 70 {
+				want = pattern[px][py]
+			}
+			if got[x][y] != want {
+				t.Fatalf("Region()[%v][%v] = %v, want %v", x, y, got[x][y], want)
+			}
+		}
+	}
+
+	if err := u.SetRegion([][]uint{{0, 3}}, 0, 0); err == nil {
+		t.Errorf("SetRegion() with an invalid state should return an error")
+	}
+}
+
+func TestUniverse_Step(t *testing.T) {
+	// a glider gun and a glider heading the other way, checked against a grid large enough that neither reaches its edges
+	const size, offset, generations = 400, 200, 150
+
+	gun := [][2]int{
+		{0, 4}, {0, 5}, {1, 4}, {1, 5},
+		{10, 3}, {10, 4}, {10, 5}, {11, 2}, {11, 6}, {12, 1}, {12, 7}, {13, 1}, {13, 7}, {14, 4}, {15, 2}, {15, 6}, {16, 3}, {16, 4}, {16, 5}, {17, 4},
+		{20, 5}, {20, 6}, {20, 7}, {21, 5}, {21, 6}, {21, 7}, {22, 4}, {22, 8}, {24, 3}, {24, 4}, {24, 8}, {24, 9},
+		{34, 6}, {34, 7}, {35, 6}, {35, 7},
+		// a glider heading down and left
+		{-10, -10}, {-9, -10}, {-8, -10}, {-8, -11}, {-9, -12},
+	}
+
+	for _, topology := range []Topology{Square, Hexagonal} {
+		t.Run(fmt.Sprintf("topology %v", topology), func(t *testing.T) {
+			a := newLocalConways()
+			if err := a.SetTopology(topology); err != nil {
+				t.Fatalf("SetTopology() error = %v", err)
+			}
+			u, err := NewUniverse(a, 0)
+			if err != nil {
+				t.Fatalf("NewUniverse() error = %v", err)
+			}
+
+			c, next := NewGrid(size, size, 0), NewGrid(size, size, 0)
+			for _, p := range gun {
+				c[p[0]+offset][p[1]+offset] = 1
+				u.Set(p[0], p[1], 1)
+			}
+
+			for generation := range generations {
+				a.StepWith(c, next, StepOptions{Generation: uint(generation)})
+				c, next = next, c
+				u.Step()
+
+				if got := u.Region(-offset, -offset, size, size); !reflect.DeepEqual(got, c) {
+					t.Fatalf("generation %v: universe differs from a fixed grid", generation+1)
+				}
+			}
+
+			if got := u.Generation(); got != generations {
+				t.Errorf("Generation() = %v, want %v", got, generations)
+			}
+		})
+	}
+}
+
+func TestUniverse_Step_Empty(t *testing.T) {
+	u, err := NewUniverse(newLocalConways(), 0)
+	if err != nil {
+		t.Fatalf("NewUniverse() error = %v", err)
+	}
+
+	// a blinker and a lone cell, which dies, leaving its chunk to be dropped
+	u.Set(10, 10, 1)
+	u.Set(10, 11, 1)
+	u.Set(10, 12, 1)
+	u.Set(1000, 1000, 1)
+	u.Step()
+
+	if got := u.CountChunks(); got != 1 {
+		t.Errorf("CountChunks() = %v, want 1", got)
+	}
+	if x, y, width, height, _ := u.Bounds(); x != 9 || y != 11 || width != 3 || height != 1 {
+		t.Errorf("Bounds() = (%v, %v, %v, %v), want (9, 11, 3, 1)", x, y, width, height)
+	}
+}
+
+func TestUniverse_Step_Radius(t *testing.T) {
+	// a signal that travels 100 cells left every generation, jumping more than a whole chunk each time
+	const jump, width, height, offset, generations = 100, 800, 64, 700, 6
+
+	transitions := NewTransitionSet()
+	transitions.AddTransition(0, 1, func(cell Cell) bool {
+		right, err := cell.Neighbour(jump, 0)
+		return err == nil && right == 1
+	})
+	transitions.AddTransition(1, 0, func(cell Cell) bool { return true })
+	a, err := NewAutomaton(transitions, []Rgb{{}, {R: 1}})
+	if err != nil {
+		t.Fatalf("NewAutomaton() error = %v", err)
+	}
+	a.SetLocality(jump)
+
+	u, err := NewUniverse(a, 0)
+	if err != nil {
+		t.Fatalf("NewUniverse() error = %v", err)
+	}
+
+	c, next := NewGrid(width, height, 0), NewGrid(width, height, 0)
+	c[offset][10] = 1
+	u.Set(0, 10, 1)
+
+	for generation := range generations {
+		a.StepWith(c, next, StepOptions{Generation: uint(generation)})
+		c, next = next, c
+		u.Step()
+
+		if got := u.Region(-offset, 0, width, height); !reflect.DeepEqual(got, c) {
+			t.Fatalf("generation %v: universe differs from a fixed grid", generation+1)
+		}
+	}
+
+	if got := u.Get(-jump*generations, 10); got != 1 {
+		t.Errorf("Get(%v, 10) = %v, want the signal to have reached it", -jump*generations, got)
+	}
+}
@@ -0,0 +1,148 @@
+// Package network loads graphs for automata simulated on a [model.Graph], and runs them headlessly.
+//
+// Graphs are read from edge lists, the plain text format used by most network analysis tools, where each line joins two nodes:
+//
+//	# comments start with # or %
+//	alice bob
+//	bob carol 0.5
+//	carol,dave
+//
+// Nodes may be named with any word, and are numbered from 0 in the order they first appear. Fields are separated by whitespace or commas,
+// and any fields after the first two, such as weights, are ignored.
+package network
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/michael-ryan/cellularautomata/v2/model"
+	"github.com/michael-ryan/cellularautomata/v2/simulation"
+	"github.com/michael-ryan/cellularautomata/v2/stats"
+)
+
+// Network is a graph read from an edge list, along with the names of its nodes.
+type Network struct {
+	Graph *model.Graph
+	// Names[n] is the name node n was given in the edge list.
+	Names []string
+}
+
+// Node returns the number of the node with the given name, and whether there is such a node.
+func (n Network) Node(name string) (uint, bool) {
+	for i, nodeName := range n.Names {
+		if nodeName == name {
+			return uint(i), true
+		}
+	}
+	return 0, false
+}
+
+// ReadEdgeList parses a graph from an edge list. See the package documentation for the format.
+//
+// If directed is false, each line joins its nodes with an undirected edge. If directed is true, each line "a b" adds an arc from a to b, so b is influenced by a. See [model.Graph.AddArc].
+func ReadEdgeList(r io.Reader, directed bool) (Network, error) {
+	scanner := bufio.NewScanner(r)
+
+	var (
+		names []string
+		index = make(map[string]uint)
+		edges [][2]uint
+	)
+
+	node := func(name string) uint {
+		if i, ok := index[name]; ok {
+			return i
+		}
+		i := uint(len(names))
+		index[name] = i
+		names = append(names, name)
+		return i
+	}
+
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == '#' || text[0] == '%' {
+			continue
+		}
+
+		fields := strings.FieldsFunc(text, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		if len(fields) < 2 {
+			return Network{}, fmt.Errorf("line %v: an edge must join two nodes, got %q", line, text)
+		}
+
+		edges = append(edges, [2]uint{node(fields[0]), node(fields[1])})
+	}
+	if err := scanner.Err(); err != nil {
+		return Network{}, err
+	}
+
+	g := model.NewGraph(uint(len(names)))
+	for _, edge := range edges {
+		var err error
+		if directed {
+			err = g.AddArc(edge[0], edge[1])
+		} else {
+			err = g.AddEdge(edge[0], edge[1])
+		}
+		if err != nil {
+			return Network{}, err
+		}
+	}
+
+	return Network{Graph: g, Names: names}, nil
+}
+
+// ReadEdgeListFile parses a graph from the edge list in the file at path. See [ReadEdgeList].
+func ReadEdgeListFile(path string, directed bool) (Network, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Network{}, err
+	}
+	defer f.Close()
+
+	return ReadEdgeList(f, directed)
+}
+
+// Run simulates automaton on graph for the given number of generations, starting from the given state of each node,
+// and returns a [stats.Recorder] holding the population of each state in every generation.
+// initial[n] is the starting state of node n, and there must be one per node.
+//
+// The automaton is put on the graph with [model.Automaton.SetGraph], and left there when Run returns.
+// The records start with generation 0, and can be exported with [stats.Recorder.WriteCSV] or [stats.Recorder.WriteJSONLines].
+// As with [simulation.Simulation.Run], if generations is 0, Run continues until ctx is cancelled.
+// If ctx is cancelled, Run stops early, returning the records so far along with the context's error.
+func Run(ctx context.Context, automaton *model.Automaton, graph *model.Graph, initial []uint, generations uint) (*stats.Recorder, error) {
+	if automaton == nil || graph == nil {
+		return nil, fmt.Errorf("automaton and graph must not be nil")
+	}
+
+	if uint(len(initial)) != graph.CountNodes() {
+		return nil, fmt.Errorf("there are %v initial states, but the graph has %v nodes", len(initial), graph.CountNodes())
+	}
+
+	automaton.SetGraph(graph)
+
+	cells := graph.NewGrid(0)
+	for n, state := range initial {
+		cells[n][0] = state
+	}
+
+	sim, err := simulation.New(automaton, cells)
+	if err != nil {
+		return nil, err
+	}
+
+	recorder := stats.NewRecorder(automaton.CountStates())
+	sim.Observe(recorder)
+
+	return recorder, sim.Run(ctx, generations)
+}
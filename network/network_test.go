@@ -0,0 +1,141 @@
+package network
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/michael-ryan/cellularautomata/v2/model"
+)
+
+func TestReadEdgeList(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		directed       bool
+		wantNames      []string
+		wantNeighbours [][]uint
+		wantErr        bool
+	}{
+		{
+			name:           "undirected",
+			input:          "# a triangle with a tail\na b\nb c\nc a\n\nc d\n",
+			wantNames:      []string{"a", "b", "c", "d"},
+			wantNeighbours: [][]uint{{1, 2}, {0, 2}, {1, 0, 3}, {2}},
+		},
+		{
+			name:           "directed",
+			input:          "a b\nb c\n",
+			directed:       true,
+			wantNames:      []string{"a", "b", "c"},
+			wantNeighbours: [][]uint{nil, {0}, {1}},
+		},
+		{
+			name:           "commas, tabs and weights",
+			input:          "% matrix market style comment\n1,2,0.5\n2\t3 1.0\n",
+			wantNames:      []string{"1", "2", "3"},
+			wantNeighbours: [][]uint{{1}, {0, 2}, {1}},
+		},
+		{
+			name:           "self loop",
+			input:          "a a\n",
+			wantNames:      []string{"a"},
+			wantNeighbours: [][]uint{{0}},
+		},
+		{
+			name:    "one node",
+			input:   "a b\nc\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadEdgeList(strings.NewReader(tt.input), tt.directed)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadEdgeList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(got.Names, tt.wantNames) {
+				t.Errorf("ReadEdgeList().Names = %v, want %v", got.Names, tt.wantNames)
+			}
+			for n, want := range tt.wantNeighbours {
+				if neighbours := got.Graph.Neighbours(uint(n)); !reflect.DeepEqual(neighbours, want) {
+					t.Errorf("ReadEdgeList().Graph.Neighbours(%v) = %v, want %v", n, neighbours, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNetwork_Node(t *testing.T) {
+	n := Network{Names: []string{"alice", "bob"}}
+
+	if got, ok := n.Node("bob"); !ok || got != 1 {
+		t.Errorf("Network.Node(\"bob\") = %v, %v, want 1, true", got, ok)
+	}
+	if _, ok := n.Node("carol"); ok {
+		t.Errorf("Network.Node(\"carol\") should report no such node")
+	}
+}
+
+func TestRun(t *testing.T) {
+	const (
+		susceptible = iota
+		infected
+		recovered
+	)
+
+	transitions := model.NewTransitionSet()
+	transitions.AddTransition(susceptible, infected, func(cell model.Cell) bool {
+		return cell.CountNeighbours(infected, false) > 0
+	})
+	transitions.AddTransition(infected, recovered, func(cell model.Cell) bool {
+		return true
+	})
+	a, err := model.NewAutomaton(transitions, make([]model.Rgb, 3))
+	if err != nil {
+		t.Fatalf("NewAutomaton() error = %v", err)
+	}
+
+	network, err := ReadEdgeList(strings.NewReader("a b\nb c\nc d\n"), false)
+	if err != nil {
+		t.Fatalf("ReadEdgeList() error = %v", err)
+	}
+
+	recorder, err := Run(context.Background(), a, network.Graph, []uint{infected, susceptible, susceptible, susceptible}, 4)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// the infection travels down the path one node per generation, and each node recovers after one generation
+	want := [][]uint{
+		{3, 1, 0},
+		{2, 1, 1},
+		{1, 1, 2},
+		{0, 1, 3},
+		{0, 0, 4},
+	}
+	records := recorder.Records()
+	if len(records) != len(want) {
+		t.Fatalf("Run() recorded %v generations, want %v", len(records), len(want))
+	}
+	for i, record := range records {
+		if !reflect.DeepEqual(record.Counts, want[i]) {
+			t.Errorf("generation %v counts = %v, want %v", record.Generation, record.Counts, want[i])
+		}
+	}
+
+	if _, err := Run(context.Background(), a, network.Graph, []uint{infected}, 1); err == nil {
+		t.Errorf("Run() should reject the wrong number of initial states")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Run(ctx, a, network.Graph, make([]uint, 4), 0); err == nil {
+		t.Errorf("Run() should return the error of a cancelled context")
+	}
+}
@@ -0,0 +1,377 @@
+// Package rle reads and writes patterns in the run length encoded (RLE) format used by Golly and most other Life software.
+//
+// Both the two-state format (b for dead, o for alive) and the extended multi-state format (. for state 0, A to X for states 1 to 24,
+// pA to pX for states 25 to 48, and so on up to state 255) are supported.
+//
+// RLE lists rows from top to bottom, whereas grids in this module are indexed as cells[x][y] with y = 0 at the bottom. This package converts between the two.
+//
+// [https://conwaylife.com/wiki/Run_Length_Encoded]
+package rle
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/michael-ryan/cellularautomata/v2/model"
+)
+
+// MaxState is the highest state that can be represented in extended RLE.
+const MaxState = 255
+
+// MaxCells is the largest number of cells a pattern read by [Read] may have, as given by the dimensions in its header.
+// Larger patterns are rejected before their grid is allocated, so that a malformed or hostile file cannot exhaust memory.
+const MaxCells = 1 << 27
+
+// lineLength is the maximum length of a line of pattern data written by [Write], as recommended by the format.
+const lineLength = 70
+
+// Pattern is a rectangular pattern of cells, as read from an RLE file.
+type Pattern struct {
+	// Cells is indexed as Cells[x][y], with y = 0 being the bottom row of the pattern.
+	Cells [][]uint
+	// Rule is the rule given in the header of the file, or empty if there was none.
+	Rule string
+}
+
+// Width returns the number of columns in the pattern.
+func (p Pattern) Width() uint {
+	return uint(len(p.Cells))
+}
+
+// Height returns the number of rows in the pattern.
+func (p Pattern) Height() uint {
+	if len(p.Cells) == 0 {
+		return 0
+	}
+	return uint(len(p.Cells[0]))
+}
+
+// Place copies the pattern into grid, with the bottom left corner of the pattern at (x, y).
+// The grid is indexed as grid[x][y], and the pattern must fit entirely within it.
+func (p Pattern) Place(grid [][]uint, x, y int) error {
+	width, height := int(p.Width()), int(p.Height())
+
+	if x < 0 || y < 0 || x+width > len(grid) || (width > 0 && y+height > len(grid[0])) {
+		gridHeight := 0
+		if len(grid) > 0 {
+			gridHeight = len(grid[0])
+		}
+		return fmt.Errorf("%vx%v pattern placed at (%v, %v) does not fit on a %vx%v grid", width, height, x, y, len(grid), gridHeight)
+	}
+
+	for px := range p.Cells {
+		copy(grid[x+px][y:y+height], p.Cells[px])
+	}
+
+	return nil
+}
+
+// Read parses an RLE pattern from r.
+//
+// Comment lines (starting with #) are ignored. The header line must give the dimensions of the pattern, and may give a rule:
+//
+//	x = 3, y = 3, rule = B3/S23
+//
+// Patterns whose header gives more than [MaxCells] cells are rejected.
+func Read(r io.Reader) (Pattern, error) {
+	scanner := bufio.NewScanner(r)
+
+	var (
+		p      Pattern
+		header bool
+		body   strings.Builder
+	)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if !header {
+			width, height, rule, err := parseHeader(line)
+			if err != nil {
+				return Pattern{}, err
+			}
+			p.Cells = model.NewGrid(width, height, 0)
+			p.Rule = rule
+			header = true
+			continue
+		}
+
+		body.WriteString(line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return Pattern{}, fmt.Errorf("failed to read RLE: %w", err)
+	}
+
+	if !header {
+		return Pattern{}, fmt.Errorf("RLE has no header line")
+	}
+
+	if err := parseBody(body.String(), p.Cells); err != nil {
+		return Pattern{}, err
+	}
+
+	return p, nil
+}
+
+// parseHeader parses a line of the form "x = 3, y = 3, rule = B3/S23".
+// The rule runs to the end of the line, as rules such as "R5,C0,M1,S34..58,B34..45,NM" contain commas themselves.
+func parseHeader(line string) (width, height uint, rule string, err error) {
+	seen := make(map[string]bool)
+
+	for rest := line; rest != ""; {
+		field, next, _ := strings.Cut(rest, ",")
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			return 0, 0, "", fmt.Errorf("malformed RLE header %q, expected key = value pairs", line)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		seen[key] = true
+
+		switch key {
+		case "x", "y":
+			n, err := strconv.ParseUint(value, 10, 0)
+			if err != nil {
+				return 0, 0, "", fmt.Errorf("malformed RLE header %q, invalid %v: %w", line, key, err)
+			}
+			if key == "x" {
+				width = uint(n)
+			} else {
+				height = uint(n)
+			}
+		case "rule":
+			_, value, _ = strings.Cut(rest, "=")
+			rule = strings.TrimSpace(value)
+			next = ""
+		}
+
+		rest = next
+	}
+
+	if !seen["x"] || !seen["y"] {
+		return 0, 0, "", fmt.Errorf("malformed RLE header %q, x and y must both be given", line)
+	}
+
+	// each bound is checked alone first, so the product cannot overflow
+	if width > MaxCells || height > MaxCells || width*height > MaxCells {
+		return 0, 0, "", fmt.Errorf("RLE pattern of %vx%v cells is too large, at most %v cells are supported", width, height, MaxCells)
+	}
+
+	return width, height, rule, nil
+}
+
+// parseBody decodes the run length encoded pattern data into cells, which must already have the dimensions given in the header.
+func parseBody(body string, cells [][]uint) error {
+	width, height := len(cells), 0
+	if width > 0 {
+		height = len(cells[0])
+	}
+
+	x, row := 0, 0
+	for i := 0; i < len(body); {
+		start := i
+		for i < len(body) && body[i] >= '0' && body[i] <= '9' {
+			i++
+		}
+		count := 1
+		if i > start {
+			n, err := strconv.Atoi(body[start:i])
+			if err != nil {
+				return fmt.Errorf("invalid run count %q in RLE body: %w", body[start:i], err)
+			}
+			count = n
+		}
+
+		if i >= len(body) {
+			return fmt.Errorf("RLE body ends with a run count and no tag")
+		}
+
+		tag := body[i]
+		i++
+
+		var state uint
+		switch {
+		case tag == '!':
+			return nil
+		case tag == '$':
+			x = 0
+			row += count
+			continue
+		case tag == 'b' || tag == '.':
+			state = 0
+		case tag == 'o':
+			state = 1
+		case tag >= 'A' && tag <= 'X':
+			state = uint(tag-'A') + 1
+		case tag >= 'p' && tag <= 'y':
+			if i >= len(body) || body[i] < 'A' || body[i] > 'X' {
+				return fmt.Errorf("prefix %q in RLE body must be followed by a letter A to X", tag)
+			}
+			state = 24*uint(tag-'p'+1) + uint(body[i]-'A') + 1
+			i++
+		case tag == ' ' || tag == '\t':
+			continue
+		default:
+			return fmt.Errorf("unexpected character %q in RLE body", tag)
+		}
+
+		if state > MaxState {
+			return fmt.Errorf("state %v in RLE body exceeds the maximum of %v", state, MaxState)
+		}
+
+		if x+count > width || row >= height {
+			return fmt.Errorf("RLE body overflows the %vx%v pattern given in its header, at row %v", width, height, row)
+		}
+
+		// rows run top to bottom, but y = 0 is the bottom
+		for range count {
+			cells[x][height-1-row] = state
+			x++
+		}
+	}
+
+	return nil
+}
+
+// Write encodes cells as an RLE pattern, indexed as cells[x][y] with y = 0 being the bottom row.
+// If rule is not empty, it is included in the header.
+//
+// If every cell is in state 0 or 1, the two-state format is written. Otherwise, the extended multi-state format is used.
+func Write(w io.Writer, cells [][]uint, rule string) error {
+	width, height := len(cells), 0
+	if width > 0 {
+		height = len(cells[0])
+	}
+
+	multiState := false
+	for x := range cells {
+		if len(cells[x]) != height {
+			return fmt.Errorf("grid is not rectangular, column %v has height %v but column 0 has height %v", x, len(cells[x]), height)
+		}
+		for y, state := range cells[x] {
+			if state > MaxState {
+				return fmt.Errorf("cell (%v, %v) has state %v, which exceeds the maximum of %v representable in RLE", x, y, state, MaxState)
+			}
+			multiState = multiState || state > 1
+		}
+	}
+
+	header := fmt.Sprintf("x = %v, y = %v", width, height)
+	if rule != "" {
+		header += ", rule = " + rule
+	}
+
+	out := &lineWriter{}
+	pendingRows, started := 0, false
+	for row := range height {
+		y := height - 1 - row
+
+		// trailing dead cells in a row are implied, so find where the row really ends
+		end := width
+		for end > 0 && cells[end-1][y] == 0 {
+			end--
+		}
+
+		if end == 0 {
+			pendingRows++
+			continue
+		}
+
+		if started {
+			out.run(pendingRows+1, "$")
+		} else if pendingRows > 0 {
+			out.run(pendingRows, "$")
+		}
+		pendingRows, started = 0, true
+
+		for x := 0; x < end; {
+			state := cells[x][y]
+			run := 1
+			for x+run < end && cells[x+run][y] == state {
+				run++
+			}
+			out.run(run, tag(state, multiState))
+			x += run
+		}
+	}
+	out.run(1, "!")
+
+	_, err := fmt.Fprintf(w, "%v\n%v\n", header, strings.Join(out.lines, "\n"))
+	return err
+}
+
+// tag returns the RLE tag for a single cell in the given state.
+func tag(state uint, multiState bool) string {
+	if !multiState {
+		if state == 0 {
+			return "b"
+		}
+		return "o"
+	}
+
+	if state == 0 {
+		return "."
+	}
+
+	letter := string(rune('A' + (state-1)%24))
+	if state <= 24 {
+		return letter
+	}
+	return string(rune('p'+(state-25)/24)) + letter
+}
+
+// lineWriter accumulates runs of pattern data, wrapping lines so that none exceed lineLength.
+type lineWriter struct {
+	lines []string
+	line  strings.Builder
+}
+
+func (l *lineWriter) run(count int, tag string) {
+	item := tag
+	if count > 1 {
+		item = strconv.Itoa(count) + tag
+	}
+
+	if l.line.Len()+len(item) > lineLength {
+		l.lines = append(l.lines, l.line.String())
+		l.line.Reset()
+	}
+	l.line.WriteString(item)
+
+	if tag == "!" {
+		l.lines = append(l.lines, l.line.String())
+	}
+}
+
+// ReadFile parses the RLE pattern stored in the file at path. See [Read].
+func ReadFile(path string) (Pattern, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Pattern{}, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// WriteFile encodes cells as an RLE pattern into the file at path, creating or truncating it. See [Write].
+func WriteFile(path string, cells [][]uint, rule string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := Write(f, cells, rule); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
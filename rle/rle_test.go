@@ -0,0 +1,270 @@
+package rle
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		want     [][]uint
+		wantRule string
+		wantErr  bool
+	}{
+		{
+			name: "glider",
+			input: `#N Glider
+#C A comment
+x = 3, y = 3, rule = B3/S23
+bob$2bo$3o!
+`,
+			// columns, bottom to top
+			want:     [][]uint{{1, 0, 0}, {1, 0, 1}, {1, 1, 0}},
+			wantRule: "B3/S23",
+			wantErr:  false,
+		},
+		{
+			name:     "no rule and split lines",
+			input:    "x = 4, y = 2\n2o\n$b\n3o!",
+			want:     [][]uint{{0, 1}, {1, 1}, {1, 0}, {1, 0}},
+			wantRule: "",
+			wantErr:  false,
+		},
+		{
+			name:     "multi-state",
+			input:    "x=3,y=2,rule=B2/S/3\n.AB$pAqX!",
+			want:     [][]uint{{25, 0}, {72, 1}, {0, 2}},
+			wantRule: "B2/S/3",
+			wantErr:  false,
+		},
+		{
+			name:     "rule with commas",
+			input:    "x = 3, y = 1, rule = R5,C0,M1,S34..58,B34..45,NM\no2b!",
+			want:     [][]uint{{1}, {0}, {0}},
+			wantRule: "R5,C0,M1,S34..58,B34..45,NM",
+			wantErr:  false,
+		},
+		{
+			name:     "blank rows",
+			input:    "x = 1, y = 3\no2$o!",
+			want:     [][]uint{{1, 0, 1}},
+			wantRule: "",
+			wantErr:  false,
+		},
+		{
+			name:    "no header",
+			input:   "#C nothing here\n",
+			wantErr: true,
+		},
+		{
+			name:    "bad header",
+			input:   "x = 3\nooo!",
+			wantErr: true,
+		},
+		{
+			name:    "too large",
+			input:   "x = 4000000000, y = 4000000000\no!",
+			wantErr: true,
+		},
+		{
+			name:    "too wide",
+			input:   "x = 4000000000, y = 0\n!",
+			wantErr: true,
+		},
+		{
+			name:    "overflow",
+			input:   "x = 2, y = 1\nooo!",
+			wantErr: true,
+		},
+		{
+			name:    "bad tag",
+			input:   "x = 2, y = 1\noz!",
+			wantErr: true,
+		},
+		{
+			name:    "state too high",
+			input:   "x = 1, y = 1\nyX!",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Read(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Read() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got.Cells, tt.want) {
+				t.Errorf("Read().Cells = %v, want %v", got.Cells, tt.want)
+			}
+			if got.Rule != tt.wantRule {
+				t.Errorf("Read().Rule = %q, want %q", got.Rule, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name  string
+		cells [][]uint
+		rule  string
+		want  string
+	}{
+		{
+			name:  "glider",
+			cells: [][]uint{{1, 0, 0}, {1, 0, 1}, {1, 1, 0}},
+			rule:  "B3/S23",
+			want:  "x = 3, y = 3, rule = B3/S23\nbo$2bo$3o!\n",
+		},
+		{
+			name:  "blank rows",
+			cells: [][]uint{{1, 0, 0, 0}, {0, 0, 0, 0}},
+			rule:  "",
+			want:  "x = 2, y = 4\n3$o!\n",
+		},
+		{
+			name:  "multi-state",
+			cells: [][]uint{{25, 0}, {72, 1}, {0, 2}},
+			rule:  "",
+			want:  "x = 3, y = 2\n.AB$pAqX!\n",
+		},
+		{
+			name:  "long line",
+			cells: checkerboard(80, 1),
+			rule:  "",
+			want:  "x = 80, y = 1\n" + strings.Repeat("ob", 35) + "\n" + strings.Repeat("ob", 4) + "o!\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			if err := Write(w, tt.cells, tt.rule); err != nil {
+				t.Errorf("Write() error = %v", err)
+				return
+			}
+			if got := w.String(); got != tt.want {
+				t.Errorf("Write() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrite_RoundTrip(t *testing.T) {
+	cells := [][]uint{
+		{0, 3, 0, 0, 0},
+		{200, 0, 0, 1, 0},
+		{0, 0, 0, 0, 0},
+		{7, 7, 7, 0, 49},
+	}
+
+	for _, rule := range []string{"Custom", "R5,C0,M1,S34..58,B34..45,NM"} {
+		t.Run(rule, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			if err := Write(w, cells, rule); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+
+			p, err := Read(w)
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if !reflect.DeepEqual(p.Cells, cells) {
+				t.Errorf("Read(Write()) = %v, want %v", p.Cells, cells)
+			}
+			if p.Rule != rule {
+				t.Errorf("Read(Write()).Rule = %q, want %q", p.Rule, rule)
+			}
+		})
+	}
+}
+
+func TestPattern_Place(t *testing.T) {
+	p := Pattern{Cells: [][]uint{{1, 2}, {3, 4}}}
+
+	tests := []struct {
+		name    string
+		x, y    int
+		want    [][]uint
+		wantErr bool
+	}{
+		{
+			name:    "corner",
+			x:       0,
+			y:       0,
+			want:    [][]uint{{1, 2, 0}, {3, 4, 0}, {0, 0, 0}},
+			wantErr: false,
+		},
+		{
+			name:    "offset",
+			x:       1,
+			y:       1,
+			want:    [][]uint{{0, 0, 0}, {0, 1, 2}, {0, 3, 4}},
+			wantErr: false,
+		},
+		{
+			name:    "off grid",
+			x:       2,
+			y:       0,
+			wantErr: true,
+		},
+		{
+			name:    "negative",
+			x:       0,
+			y:       -1,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grid := [][]uint{{0, 0, 0}, {0, 0, 0}, {0, 0, 0}}
+			err := p.Place(grid, tt.x, tt.y)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Pattern.Place() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && !reflect.DeepEqual(grid, tt.want) {
+				t.Errorf("Pattern.Place() grid = %v, want %v", grid, tt.want)
+			}
+		})
+	}
+}
+
+func checkerboard(width, height int) [][]uint {
+	cells := make([][]uint, width)
+	for x := range cells {
+		cells[x] = make([]uint, height)
+		for y := range cells[x] {
+			cells[x][y] = uint((x + y + 1) % 2)
+		}
+	}
+	return cells
+}
+
+func TestWriteFile_ReadFile(t *testing.T) {
+	path := t.TempDir() + "/glider.rle"
+	cells := [][]uint{{1, 0, 0}, {1, 0, 1}, {1, 1, 0}}
+
+	if err := WriteFile(path, cells, "B3/S23"); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	p, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !reflect.DeepEqual(p.Cells, cells) {
+		t.Errorf("ReadFile().Cells = %v, want %v", p.Cells, cells)
+	}
+
+	if _, err := ReadFile(path + ".missing"); err == nil {
+		t.Errorf("ReadFile() of a missing file returned no error")
+	}
+}
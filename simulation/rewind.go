@@ -0,0 +1,121 @@
+package simulation
+
+import (
+	"fmt"
+
+	"github.com/michael-ryan/cellularautomata/v2/model"
+)
+
+// rewind is a ring buffer of the grids of past generations, oldest first.
+type rewind struct {
+	grids [][][]uint
+	// start is the index in grids of the oldest generation kept, and count is the number kept
+	start, count int
+	// earliest is the generation of the oldest grid kept
+	earliest uint
+}
+
+// push adds a copy of cells as the grid of the generation after the newest kept, forgetting the oldest if the buffer is full.
+func (r *rewind) push(cells [][]uint) {
+	if len(r.grids) == 0 {
+		return
+	}
+
+	i := (r.start + r.count) % len(r.grids)
+	if r.count == len(r.grids) {
+		r.start = (r.start + 1) % len(r.grids)
+		r.earliest++
+	} else {
+		r.count++
+	}
+
+	if r.grids[i] == nil {
+		r.grids[i] = model.NewGrid(uint(len(cells)), uint(len(cells[0])), 0)
+	}
+	for x := range cells {
+		copy(r.grids[i][x], cells[x])
+	}
+}
+
+// at returns the grid kept for generation, which must be between earliest and earliest + count - 1.
+func (r *rewind) at(generation uint) [][]uint {
+	return r.grids[(r.start+int(generation-r.earliest))%len(r.grids)]
+}
+
+// SetHistory keeps the grids of up to n past generations, so the simulation can be viewed with [Simulation.CellsAt] and returned to with [Simulation.Rewind].
+// The history starts at the current generation. If n is 0, which is the default, no history is kept.
+//
+// Each generation kept holds a full copy of the grid.
+func (s *Simulation) SetHistory(n uint) {
+	s.rewind = rewind{grids: make([][][]uint, n), earliest: s.generation}
+}
+
+// Earliest returns the earliest generation the simulation can be rewound to, which is the current generation if no history is kept.
+func (s *Simulation) Earliest() uint {
+	if s.rewind.count == 0 {
+		return s.generation
+	}
+	return s.rewind.earliest
+}
+
+// CellsAt returns a copy of the grid of a past generation, indexed as cells[x][y], or of the current grid if generation is the current generation.
+// It returns an error if generation is in the future, or is older than the history kept. See [Simulation.SetHistory].
+func (s *Simulation) CellsAt(generation uint) ([][]uint, error) {
+	if err := s.checkHistory(generation); err != nil {
+		return nil, err
+	}
+
+	if generation == s.generation {
+		return s.Cells(), nil
+	}
+	return copyCells(s.rewind.at(generation)), nil
+}
+
+// Rewind returns the simulation to a past generation, forgetting every generation after it, so stepping carries on from there.
+// Observers that implement [Rewinder] are told of the rewind, so they can forget what they observed after generation. Other observers are not notified.
+// It returns an error if generation is in the future, or is older than the history kept. See [Simulation.SetHistory].
+//
+// Stepping after a rewind gives the same generations as before, even for stochastic automata, unless the grid has been changed.
+func (s *Simulation) Rewind(generation uint) error {
+	if err := s.checkHistory(generation); err != nil {
+		return err
+	}
+
+	if generation == s.generation {
+		return nil
+	}
+
+	past := s.rewind.at(generation)
+	for x := range past {
+		copy(s.cells[x], past[x])
+	}
+	s.rewind.count = int(generation - s.rewind.earliest)
+	s.generation = generation
+
+	// the grid has jumped, so what changed in the last step says nothing about the next
+	s.activity.Reset()
+	if s.trace != nil {
+		s.trace.Fired = nil
+	}
+
+	for _, o := range s.observers {
+		if r, ok := o.(Rewinder); ok {
+			r.Rewound(s.generation)
+		}
+	}
+
+	return nil
+}
+
+// checkHistory returns an error if the grid of generation is not available.
+func (s *Simulation) checkHistory(generation uint) error {
+	if generation > s.generation {
+		return fmt.Errorf("generation %v has not been reached yet, the simulation is at generation %v", generation, s.generation)
+	}
+
+	if generation < s.Earliest() {
+		return fmt.Errorf("generation %v is no longer kept, the earliest kept is generation %v", generation, s.Earliest())
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package simulation
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/michael-ryan/cellularautomata/v2/examples"
+	"github.com/michael-ryan/cellularautomata/v2/model"
+)
+
+// newForest returns a seeded simulation of a stochastic automaton, and the grid of each of its first generations.
+func newForest(t *testing.T, generations int) (*Simulation, [][][]uint) {
+	t.Helper()
+
+	a := examples.NewForest()
+	a.SetSeed(3)
+	s, err := New(a, model.NewGrid(24, 24, 1))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	grids := [][][]uint{s.Cells()}
+	reference, err := New(a, model.NewGrid(24, 24, 1))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	for range generations {
+		reference.Step()
+		grids = append(grids, reference.Cells())
+	}
+
+	return s, grids
+}
+
+type rewindingObserver struct {
+	recordingObserver
+	rewound []uint
+}
+
+func (r *rewindingObserver) Rewound(generation uint) {
+	r.rewound = append(r.rewound, generation)
+}
+
+func TestSimulation_CellsAt(t *testing.T) {
+	s, grids := newForest(t, 20)
+	s.SetHistory(5)
+	if err := s.Run(context.Background(), 20); err != nil {
+		t.Fatalf("Simulation.Run() error = %v", err)
+	}
+
+	if got := s.Earliest(); got != 15 {
+		t.Errorf("Simulation.Earliest() = %v, want 15", got)
+	}
+
+	for generation := uint(15); generation <= 20; generation++ {
+		got, err := s.CellsAt(generation)
+		if err != nil {
+			t.Fatalf("Simulation.CellsAt(%v) error = %v", generation, err)
+		}
+		if !reflect.DeepEqual(got, grids[generation]) {
+			t.Errorf("Simulation.CellsAt(%v) differs from generation %v", generation, generation)
+		}
+	}
+
+	for _, generation := range []uint{14, 21} {
+		if _, err := s.CellsAt(generation); err == nil {
+			t.Errorf("Simulation.CellsAt(%v) should return an error", generation)
+		}
+	}
+}
+
+func TestSimulation_Rewind(t *testing.T) {
+	s, grids := newForest(t, 30)
+	s.SetHistory(10)
+	o := &rewindingObserver{}
+	s.Observe(o)
+
+	if err := s.Run(context.Background(), 20); err != nil {
+		t.Fatalf("Simulation.Run() error = %v", err)
+	}
+
+	if err := s.Rewind(5); err == nil {
+		t.Errorf("Simulation.Rewind(5) should return an error, as it is older than the history")
+	}
+
+	if err := s.Rewind(12); err != nil {
+		t.Fatalf("Simulation.Rewind(12) error = %v", err)
+	}
+	if got := s.Generation(); got != 12 {
+		t.Errorf("Simulation.Generation() after rewinding = %v, want 12", got)
+	}
+	if got := s.Cells(); !reflect.DeepEqual(got, grids[12]) {
+		t.Errorf("Simulation.Cells() after rewinding differs from generation 12")
+	}
+	if got := len(o.observations); got != 21 {
+		t.Errorf("observer was notified %v times, want 21, as rewinding only notifies Rewinders", got)
+	}
+	if !reflect.DeepEqual(o.rewound, []uint{12}) {
+		t.Errorf("Rewinder was told of rewinds to %v, want [12]", o.rewound)
+	}
+
+	// generations after the one rewound to are forgotten
+	if _, err := s.CellsAt(13); err == nil {
+		t.Errorf("Simulation.CellsAt(13) after rewinding should return an error")
+	}
+
+	// stepping on gives the same generations as before, and carries on recording history
+	if err := s.Run(context.Background(), 18); err != nil {
+		t.Fatalf("Simulation.Run() error = %v", err)
+	}
+	if got := s.Cells(); !reflect.DeepEqual(got, grids[30]) {
+		t.Errorf("Simulation.Cells() after rewinding and stepping on differs from generation 30")
+	}
+	if got := s.Earliest(); got != 20 {
+		t.Errorf("Simulation.Earliest() = %v, want 20", got)
+	}
+	if got, err := s.CellsAt(25); err != nil || !reflect.DeepEqual(got, grids[25]) {
+		t.Errorf("Simulation.CellsAt(25) differs from generation 25, error = %v", err)
+	}
+}
+
+func TestSimulation_SetHistory_Off(t *testing.T) {
+	s, _ := newForest(t, 0)
+	if err := s.Run(context.Background(), 3); err != nil {
+		t.Fatalf("Simulation.Run() error = %v", err)
+	}
+
+	if got := s.Earliest(); got != 3 {
+		t.Errorf("Simulation.Earliest() = %v, want 3", got)
+	}
+	if err := s.Rewind(3); err != nil {
+		t.Errorf("Simulation.Rewind() to the current generation error = %v", err)
+	}
+	if err := s.Rewind(2); err == nil {
+		t.Errorf("Simulation.Rewind(2) without history should return an error")
+	}
+}
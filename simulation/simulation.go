@@ -0,0 +1,195 @@
+// Package simulation provides a headless driver for a [model.Automaton].
+//
+// It owns the grid of cells and advances it one generation at a time, without any dependency on a window or graphics context.
+// This makes it suitable for batch experiments and for running in CI on machines without X11 or OpenGL.
+package simulation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/michael-ryan/cellularautomata/v2/model"
+)
+
+// Simulation advances a grid of cells according to the rules of an [model.Automaton]. You should use the [New] function to create one.
+type Simulation struct {
+	automaton  *model.Automaton
+	cells      [][]uint
+	next       [][]uint
+	generation uint
+	observers  []Observer
+	trace      *model.Trace
+	activity   model.Activity
+	rewind     rewind
+}
+
+// Observer is notified by a [Simulation] as it advances. Register one with [Simulation.Observe].
+type Observer interface {
+	// Observe is called after every step, with the generation that has just been reached, and the grids before and after the step.
+	// It is also called once on registration, with previous set to nil.
+	//
+	// The grids are owned by the simulation, and must not be modified or retained after Observe returns.
+	Observe(generation uint, previous, current [][]uint)
+}
+
+// TraceObserver is an [Observer] that also wants to know which transitions fired in each step.
+// Registering one with [Simulation.Observe] turns on tracing for the rest of the simulation.
+type TraceObserver interface {
+	Observer
+	// ObserveTrace is called after every step, before Observe, with the generation that has just been reached and the trace of the step that reached it.
+	//
+	// The trace is owned by the simulation, and must not be modified or retained after ObserveTrace returns.
+	ObserveTrace(generation uint, trace *model.Trace)
+}
+
+// Rewinder is an [Observer] that also wants to know when the simulation is rewound with [Simulation.Rewind].
+type Rewinder interface {
+	Observer
+	// Rewound is called when the simulation is rewound to generation, which the observer has already been notified of.
+	// Every later generation it was notified of no longer happened, and the next step reaches generation + 1 again.
+	Rewound(generation uint)
+}
+
+// New constructs a Simulation of automaton, starting from the given grid of cells at generation 0.
+// The grid is indexed as cells[x][y], must be rectangular and non-empty, and every cell must be a valid state of automaton.
+// If automaton is simulated on a [model.Graph], the grid must have one column per node and a height of 1, as allocated by [model.Graph.NewGrid].
+//
+// The grid is copied, so later changes to cells do not affect the simulation.
+func New(automaton *model.Automaton, cells [][]uint) (*Simulation, error) {
+	if automaton == nil {
+		return nil, fmt.Errorf("automaton must not be nil")
+	}
+
+	if len(cells) == 0 || len(cells[0]) == 0 {
+		return nil, fmt.Errorf("grid must have at least one cell")
+	}
+
+	states := automaton.CountStates()
+	height := len(cells[0])
+	for x, column := range cells {
+		if len(column) != height {
+			return nil, fmt.Errorf("grid is not rectangular, column %v has height %v but column 0 has height %v", x, len(column), height)
+		}
+
+		for y, state := range column {
+			if state >= states {
+				return nil, fmt.Errorf("cell (%v, %v) has invalid state %v, there are only %v states defined (max = %v)", x, y, state, states, states-1)
+			}
+		}
+	}
+
+	if g := automaton.GetGraph(); g != nil && (uint(len(cells)) != g.CountNodes() || height != 1) {
+		return nil, fmt.Errorf("grid is %vx%v, but the automaton's graph needs one column per node (%vx1)", len(cells), height, g.CountNodes())
+	}
+
+	s := &Simulation{
+		automaton: automaton,
+		cells:     model.NewGrid(uint(len(cells)), uint(height), 0),
+		next:      model.NewGrid(uint(len(cells)), uint(height), 0),
+	}
+	for x := range cells {
+		copy(s.cells[x], cells[x])
+	}
+
+	return s, nil
+}
+
+// Step advances the simulation by a single generation.
+//
+// If the automaton declares its locality with [model.Automaton.SetLocality], parts of the grid where nothing has changed recently are skipped. See [model.Activity].
+func (s *Simulation) Step() {
+	s.rewind.push(s.cells)
+	s.automaton.StepWith(s.cells, s.next, model.StepOptions{Trace: s.trace, Generation: s.generation, Activity: &s.activity})
+	s.cells, s.next = s.next, s.cells
+	s.generation++
+
+	for _, o := range s.observers {
+		if t, ok := o.(TraceObserver); ok {
+			t.ObserveTrace(s.generation, s.trace)
+		}
+		o.Observe(s.generation, s.next, s.cells)
+	}
+}
+
+// Observe registers o to be notified after every step. It is immediately notified of the current generation.
+//
+// If o is a [TraceObserver], tracing is turned on, as if by [Simulation.SetTracing].
+func (s *Simulation) Observe(o Observer) {
+	if _, ok := o.(TraceObserver); ok && s.trace == nil {
+		s.SetTracing(true, false)
+	}
+
+	s.observers = append(s.observers, o)
+	o.Observe(s.generation, nil, s.cells)
+}
+
+// SetTracing turns on or off recording which transitions fire in each step. See [model.Trace].
+// If cells is true, the rule that decided the fate of each individual cell is recorded as well.
+//
+// Tracing cannot be turned off while a [TraceObserver] is registered.
+func (s *Simulation) SetTracing(enabled, cells bool) {
+	if !enabled {
+		for _, o := range s.observers {
+			if _, ok := o.(TraceObserver); ok {
+				enabled, cells = true, false
+				break
+			}
+		}
+	}
+
+	if !enabled {
+		s.trace = nil
+		return
+	}
+
+	if s.trace == nil {
+		s.trace = &model.Trace{}
+	}
+	s.trace.Cells = cells
+}
+
+// Trace returns the trace of the most recent step, or nil if tracing is off or no step has been traced yet.
+// The trace is overwritten by the next step.
+//
+// To find which rule changed a specific cell, turn on tracing of cells with [Simulation.SetTracing] and inspect [model.Trace.Rules].
+func (s *Simulation) Trace() *model.Trace {
+	if s.trace == nil || s.trace.Fired == nil {
+		return nil
+	}
+	return s.trace
+}
+
+// Run advances the simulation by n generations, checking ctx for cancellation between each one.
+// If n is 0, Run continues until ctx is cancelled.
+//
+// Run returns ctx.Err() if it was cancelled before completing, and nil otherwise.
+func (s *Simulation) Run(ctx context.Context, n uint) error {
+	for i := uint(0); n == 0 || i < n; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		s.Step()
+	}
+
+	return nil
+}
+
+// Generation reports how many generations have been simulated so far.
+func (s *Simulation) Generation() uint {
+	return s.generation
+}
+
+// Cells returns a copy of the current grid, indexed as cells[x][y].
+func (s *Simulation) Cells() [][]uint {
+	return copyCells(s.cells)
+}
+
+func copyCells(cells [][]uint) [][]uint {
+	c := make([][]uint, len(cells))
+	for x := range cells {
+		c[x] = make([]uint, len(cells[x]))
+		copy(c[x], cells[x])
+	}
+	return c
+}
@@ -0,0 +1,296 @@
+package simulation
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/michael-ryan/cellularautomata/v2/examples"
+	"github.com/michael-ryan/cellularautomata/v2/model"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		cells   [][]uint
+		wantErr bool
+	}{
+		{
+			name:    "ok",
+			cells:   [][]uint{{0, 1}, {1, 0}},
+			wantErr: false,
+		},
+		{
+			name:    "empty",
+			cells:   [][]uint{},
+			wantErr: true,
+		},
+		{
+			name:    "ragged",
+			cells:   [][]uint{{0, 1}, {1}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid state",
+			cells:   [][]uint{{0, 2}, {1, 0}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(examples.NewConways(), tt.cells)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSimulation_Step(t *testing.T) {
+	blinker := [][]uint{
+		{0, 0, 0},
+		{1, 1, 1},
+		{0, 0, 0},
+	}
+
+	s, err := New(examples.NewConways(), blinker)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	s.Step()
+	want := [][]uint{
+		{0, 1, 0},
+		{0, 1, 0},
+		{0, 1, 0},
+	}
+	if got := s.Cells(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Simulation.Cells() = %v, want %v", got, want)
+	}
+	if got := s.Generation(); got != 1 {
+		t.Errorf("Simulation.Generation() = %v, want %v", got, 1)
+	}
+
+	s.Step()
+	if got := s.Cells(); !reflect.DeepEqual(got, blinker) {
+		t.Errorf("Simulation.Cells() = %v, want %v", got, blinker)
+	}
+}
+
+func TestSimulation_Run(t *testing.T) {
+	tests := []struct {
+		name           string
+		cancel         bool
+		n              uint
+		wantGeneration uint
+		wantErr        bool
+	}{
+		{
+			name:           "ten generations",
+			n:              10,
+			wantGeneration: 10,
+			wantErr:        false,
+		},
+		{
+			name:           "cancelled",
+			cancel:         true,
+			n:              10,
+			wantGeneration: 0,
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New(examples.NewConways(), [][]uint{{0, 0}, {0, 0}})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				cancel()
+			}
+
+			err = s.Run(ctx, tt.n)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Simulation.Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := s.Generation(); got != tt.wantGeneration {
+				t.Errorf("Simulation.Generation() = %v, want %v", got, tt.wantGeneration)
+			}
+		})
+	}
+}
+
+func TestSimulation_Cells(t *testing.T) {
+	initial := [][]uint{{0, 1}, {1, 0}}
+	s, err := New(examples.NewConways(), initial)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	initial[0][0] = 1
+	cells := s.Cells()
+	cells[1][1] = 1
+
+	want := [][]uint{{0, 1}, {1, 0}}
+	if got := s.Cells(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Simulation.Cells() = %v, want %v", got, want)
+	}
+}
+
+type observation struct {
+	generation        uint
+	previous, current [][]uint
+}
+
+type recordingObserver struct {
+	observations []observation
+}
+
+func (r *recordingObserver) Observe(generation uint, previous, current [][]uint) {
+	r.observations = append(r.observations, observation{
+		generation: generation,
+		previous:   copyCells(previous),
+		current:    copyCells(current),
+	})
+}
+
+func TestSimulation_Observe(t *testing.T) {
+	blinker := [][]uint{
+		{0, 0, 0},
+		{1, 1, 1},
+		{0, 0, 0},
+	}
+	flipped := [][]uint{
+		{0, 1, 0},
+		{0, 1, 0},
+		{0, 1, 0},
+	}
+
+	s, err := New(examples.NewConways(), blinker)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	o := &recordingObserver{}
+	s.Observe(o)
+	s.Step()
+	s.Step()
+
+	want := []observation{
+		{generation: 0, previous: [][]uint{}, current: blinker},
+		{generation: 1, previous: blinker, current: flipped},
+		{generation: 2, previous: flipped, current: blinker},
+	}
+	if !reflect.DeepEqual(o.observations, want) {
+		t.Errorf("observations = %v, want %v", o.observations, want)
+	}
+}
+
+func TestSimulation_SetTracing(t *testing.T) {
+	blinker := [][]uint{
+		{0, 0, 0},
+		{1, 1, 1},
+		{0, 0, 0},
+	}
+
+	s, err := New(examples.NewConways(), blinker)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	s.Step()
+	if s.Trace() != nil {
+		t.Errorf("Simulation.Trace() = %v before tracing was enabled, want nil", s.Trace())
+	}
+
+	s.SetTracing(true, true)
+	s.Step()
+	trace := s.Trace()
+	if trace == nil {
+		t.Fatalf("Simulation.Trace() = nil after tracing was enabled")
+	}
+
+	// the centre cell survives, as no rule fires for it; the left and right neighbours of the centre are born by rule 0
+	if got := trace.Rules[1][1]; got != -1 {
+		t.Errorf("Trace.Rules[1][1] = %v, want %v", got, -1)
+	}
+	if got := trace.Rules[1][0]; got != 0 {
+		t.Errorf("Trace.Rules[1][0] = %v, want %v", got, 0)
+	}
+
+	s.SetTracing(false, false)
+	s.Step()
+	if s.Trace() != nil {
+		t.Errorf("Simulation.Trace() = %v after tracing was disabled, want nil", s.Trace())
+	}
+}
+
+func TestSimulation_Seeded(t *testing.T) {
+	run := func(seed uint64, workers uint) [][]uint {
+		a := examples.NewForest()
+		a.SetSeed(seed)
+		a.SetWorkers(workers)
+
+		s, err := New(a, model.NewGrid(32, 32, 1))
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		if err := s.Run(context.Background(), 50); err != nil {
+			t.Fatalf("Simulation.Run() error = %v", err)
+		}
+		return s.Cells()
+	}
+
+	want := run(7, 1)
+	if got := run(7, 4); !reflect.DeepEqual(got, want) {
+		t.Errorf("seeded simulations with different worker counts diverged")
+	}
+	if got := run(8, 1); reflect.DeepEqual(got, want) {
+		t.Errorf("simulations with different seeds were identical")
+	}
+}
+
+func TestNew_Graph(t *testing.T) {
+	a := examples.NewConways()
+	a.SetGraph(model.NewGraph(3))
+
+	if _, err := New(a, [][]uint{{0}, {1}, {0}}); err != nil {
+		t.Errorf("New() error = %v, want nil for one column per node", err)
+	}
+	if _, err := New(a, [][]uint{{0}, {1}}); err == nil {
+		t.Errorf("New() should reject a grid with fewer columns than nodes")
+	}
+	if _, err := New(a, [][]uint{{0, 0}, {1, 0}, {0, 0}}); err == nil {
+		t.Errorf("New() should reject a grid taller than 1")
+	}
+}
+
+func TestSimulation_Step_Activity(t *testing.T) {
+	a := examples.NewLangtons()
+
+	// an ant facing north on a black grid, which skips every tile it is not near
+	const blackAntN = 2
+	cells := model.NewGrid(64, 64, 0)
+	cells[32][32] = blackAntN
+
+	s, err := New(a, cells)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := s.Run(context.Background(), 300); err != nil {
+		t.Fatalf("Simulation.Run() error = %v", err)
+	}
+
+	want, next := cells, model.NewGrid(64, 64, 0)
+	for generation := range uint(300) {
+		a.StepWith(want, next, model.StepOptions{Generation: generation})
+		want, next = next, want
+	}
+
+	if got := s.Cells(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Simulation.Cells() after skipping quiescent tiles differs from stepping every cell")
+	}
+}
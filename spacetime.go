@@ -0,0 +1,51 @@
+package cellularautomata
+
+// spacetime draws the generations of a one-dimensional automaton as rows of a canvas, giving a spacetime diagram.
+// The first generation is drawn on the top row, and each following generation on the row beneath it.
+// Once the bottom row has been drawn, the diagram scrolls up by one row per generation, so the latest generation is always at the bottom.
+type spacetime struct {
+	// cells are the canvas cells to draw into, indexed as cells[x][y], where y = 0 is the bottom row
+	cells [][]uint
+	// rows is the number of generations drawn so far, up to the height of the canvas
+	rows int
+	// background is the state of rows not yet drawn
+	background uint
+}
+
+func newSpacetime(cells [][]uint, background uint) *spacetime {
+	return &spacetime{cells: cells, background: background}
+}
+
+// reset clears the diagram and draws row, a grid with a height of 1, as the first generation.
+func (s *spacetime) reset(row [][]uint) {
+	for x := range s.cells {
+		for y := range s.cells[x] {
+			s.cells[x][y] = s.background
+		}
+	}
+
+	s.rows = 0
+	s.push(row)
+}
+
+// push draws row, a grid with a height of 1, as the next generation, scrolling the diagram if it is full.
+func (s *spacetime) push(row [][]uint) {
+	if len(s.cells) == 0 {
+		return
+	}
+	height := len(s.cells[0])
+
+	y := height - 1 - s.rows
+	if s.rows == height {
+		for x := range s.cells {
+			copy(s.cells[x][1:], s.cells[x][:height-1])
+		}
+		y = 0
+	} else {
+		s.rows++
+	}
+
+	for x := range s.cells {
+		s.cells[x][y] = row[x][0]
+	}
+}
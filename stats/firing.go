@@ -0,0 +1,151 @@
+package stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/michael-ryan/cellularautomata/v2/model"
+	"github.com/michael-ryan/cellularautomata/v2/simulation"
+)
+
+// FiringRecord holds how many times each transition fired in a single generation.
+type FiringRecord struct {
+	// Run is the number of the run this step belongs to, counting from 0. A new run starts each time the recorder is registered with a simulation.
+	Run uint `json:"run"`
+	// Generation is the generation reached by the step these counts describe.
+	Generation uint `json:"generation"`
+	// Fired[fromState][ruleIndex] is the number of cells that rule fired for. See [model.Trace].
+	Fired [][]uint `json:"fired"`
+}
+
+// FiringRecorder is a [simulation.TraceObserver] that keeps a [FiringRecord] for every step it observes. You should use the [NewFiringRecorder] function to create one.
+//
+// Rules that never fire across a whole simulation are likely to be shadowed by an earlier rule for the same state, or to have a predicate that can never be satisfied.
+//
+// Like a [Recorder], a FiringRecorder tells apart the simulations it is registered with in turn by [FiringRecord.Run].
+//
+// A FiringRecorder is not safe for concurrent use, so records should only be read once the simulation is no longer running.
+type FiringRecorder struct {
+	records []FiringRecord
+	// runs is the number of runs observed so far, the last of which is still being observed
+	runs uint
+}
+
+var (
+	_ simulation.TraceObserver = (*FiringRecorder)(nil)
+	_ simulation.Rewinder      = (*FiringRecorder)(nil)
+)
+
+// NewFiringRecorder constructs an empty FiringRecorder.
+func NewFiringRecorder() *FiringRecorder {
+	return &FiringRecorder{}
+}
+
+// Observe implements [simulation.Observer]. It only starts a new run when previous is nil, as a FiringRecorder otherwise only needs the trace of each step.
+func (r *FiringRecorder) Observe(generation uint, previous, current [][]uint) {
+	if previous == nil || r.runs == 0 {
+		r.runs++
+	}
+}
+
+// ObserveTrace records the transition counts of a step. It implements [simulation.TraceObserver].
+func (r *FiringRecorder) ObserveTrace(generation uint, trace *model.Trace) {
+	fired := make([][]uint, len(trace.Fired))
+	for from := range trace.Fired {
+		fired[from] = make([]uint, len(trace.Fired[from]))
+		copy(fired[from], trace.Fired[from])
+	}
+
+	r.runs = max(r.runs, 1)
+	r.records = append(r.records, FiringRecord{
+		Run:        r.runs - 1,
+		Generation: generation,
+		Fired:      fired,
+	})
+}
+
+// Rewound forgets the records of every step of the current run after generation, as the simulation has been rewound to it. It implements [simulation.Rewinder].
+func (r *FiringRecorder) Rewound(generation uint) {
+	for len(r.records) > 0 && r.records[len(r.records)-1].Run == r.runs-1 && r.records[len(r.records)-1].Generation > generation {
+		r.records = r.records[:len(r.records)-1]
+	}
+}
+
+// Records returns every record observed so far, in the order they were observed.
+func (r *FiringRecorder) Records() []FiringRecord {
+	records := make([]FiringRecord, len(r.records))
+	copy(records, r.records)
+	return records
+}
+
+// Totals returns the number of times each rule fired across every recorded step, indexed as Totals[fromState][ruleIndex].
+func (r *FiringRecorder) Totals() [][]uint {
+	if len(r.records) == 0 {
+		return nil
+	}
+
+	totals := make([][]uint, len(r.records[0].Fired))
+	for from := range totals {
+		totals[from] = make([]uint, len(r.records[0].Fired[from]))
+	}
+
+	for _, record := range r.records {
+		for from := range record.Fired {
+			for rule, count := range record.Fired[from] {
+				totals[from][rule] += count
+			}
+		}
+	}
+
+	return totals
+}
+
+// WriteCSV writes every record as a row of CSV, preceded by a header row.
+//
+// The columns are the run and the generation, then fired_s_r for rule index r of each state s.
+func (r *FiringRecorder) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+
+	header := []string{"run", "generation"}
+	if len(r.records) > 0 {
+		for from := range r.records[0].Fired {
+			for rule := range r.records[0].Fired[from] {
+				header = append(header, fmt.Sprintf("fired_%v_%v", from, rule))
+			}
+		}
+	}
+
+	if err := out.Write(header); err != nil {
+		return err
+	}
+
+	for _, record := range r.records {
+		row := []string{strconv.FormatUint(uint64(record.Run), 10), strconv.FormatUint(uint64(record.Generation), 10)}
+		for from := range record.Fired {
+			for _, count := range record.Fired[from] {
+				row = append(row, strconv.FormatUint(uint64(count), 10))
+			}
+		}
+
+		if err := out.Write(row); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// WriteJSONLines writes every record as a JSON object, one per line.
+func (r *FiringRecorder) WriteJSONLines(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	for _, record := range r.records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
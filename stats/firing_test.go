@@ -0,0 +1,138 @@
+package stats
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/michael-ryan/cellularautomata/v2/examples"
+	"github.com/michael-ryan/cellularautomata/v2/simulation"
+)
+
+func TestFiringRecorder_Simulation(t *testing.T) {
+	blinker := [][]uint{
+		{0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0},
+		{0, 1, 1, 1, 0},
+		{0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0},
+	}
+
+	s, err := simulation.New(examples.NewConways(), blinker)
+	if err != nil {
+		t.Fatalf("simulation.New() error = %v", err)
+	}
+
+	r := NewFiringRecorder()
+	s.Observe(r)
+	s.Step()
+	s.Step()
+
+	// each step, two dead cells are born, and the two ends of the blinker die of underpopulation (rule 0 for alive), never overpopulation (rule 1)
+	want := []FiringRecord{
+		{Generation: 1, Fired: [][]uint{{2}, {2, 0}}},
+		{Generation: 2, Fired: [][]uint{{2}, {2, 0}}},
+	}
+	if got := r.Records(); !reflect.DeepEqual(got, want) {
+		t.Errorf("FiringRecorder.Records() = %v, want %v", got, want)
+	}
+
+	if got, want := r.Totals(), [][]uint{{4}, {4, 0}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FiringRecorder.Totals() = %v, want %v", got, want)
+	}
+
+	if s.Trace() == nil {
+		t.Errorf("Simulation.Trace() = nil, want the trace of the last step")
+	}
+}
+
+func TestFiringRecorder_Rewound(t *testing.T) {
+	s, err := simulation.New(examples.NewConways(), [][]uint{
+		{0, 0, 0},
+		{1, 1, 1},
+		{0, 0, 0},
+	})
+	if err != nil {
+		t.Fatalf("simulation.New() error = %v", err)
+	}
+	s.SetHistory(10)
+
+	r := NewFiringRecorder()
+	s.Observe(r)
+	for range 5 {
+		s.Step()
+	}
+	if err := s.Rewind(2); err != nil {
+		t.Fatalf("Simulation.Rewind() error = %v", err)
+	}
+	s.Step()
+
+	var generations []uint
+	for _, record := range r.Records() {
+		generations = append(generations, record.Generation)
+	}
+	if want := []uint{1, 2, 3}; !reflect.DeepEqual(generations, want) {
+		t.Errorf("generations recorded = %v, want %v", generations, want)
+	}
+}
+
+func TestFiringRecorder_Runs(t *testing.T) {
+	blinker := [][]uint{
+		{0, 0, 0},
+		{1, 1, 1},
+		{0, 0, 0},
+	}
+
+	r := NewFiringRecorder()
+	for _, steps := range []int{2, 0, 1} {
+		s, err := simulation.New(examples.NewConways(), blinker)
+		if err != nil {
+			t.Fatalf("simulation.New() error = %v", err)
+		}
+		s.SetHistory(10)
+		s.Observe(r)
+		for range steps {
+			s.Step()
+		}
+		// a run that is rewound before it steps has nothing to forget, so the earlier runs must be left alone
+		if steps == 0 {
+			if err := s.Rewind(0); err != nil {
+				t.Fatalf("Simulation.Rewind() error = %v", err)
+			}
+		}
+	}
+
+	var got [][2]uint
+	for _, record := range r.Records() {
+		got = append(got, [2]uint{record.Run, record.Generation})
+	}
+	if want := [][2]uint{{0, 1}, {0, 2}, {2, 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("runs and generations recorded = %v, want %v", got, want)
+	}
+}
+
+func TestFiringRecorder_Write(t *testing.T) {
+	r := NewFiringRecorder()
+	r.records = []FiringRecord{
+		{Generation: 1, Fired: [][]uint{{2}, {2, 0}}},
+		{Run: 1, Generation: 2, Fired: [][]uint{{1}, {0, 3}}},
+	}
+
+	csv := &bytes.Buffer{}
+	if err := r.WriteCSV(csv); err != nil {
+		t.Fatalf("FiringRecorder.WriteCSV() error = %v", err)
+	}
+	wantCSV := "run,generation,fired_0_0,fired_1_0,fired_1_1\n0,1,2,2,0\n1,2,1,0,3\n"
+	if got := csv.String(); got != wantCSV {
+		t.Errorf("FiringRecorder.WriteCSV() = %q, want %q", got, wantCSV)
+	}
+
+	jsonl := &bytes.Buffer{}
+	if err := r.WriteJSONLines(jsonl); err != nil {
+		t.Fatalf("FiringRecorder.WriteJSONLines() error = %v", err)
+	}
+	wantJSONL := `{"run":0,"generation":1,"fired":[[2],[2,0]]}` + "\n" + `{"run":1,"generation":2,"fired":[[1],[0,3]]}` + "\n"
+	if got := jsonl.String(); got != wantJSONL {
+		t.Errorf("FiringRecorder.WriteJSONLines() = %q, want %q", got, wantJSONL)
+	}
+}
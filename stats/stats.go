@@ -0,0 +1,171 @@
+// Package stats records how the population of each state in a simulation changes over time.
+//
+// A [Recorder] implements [simulation.Observer], so it can be registered with [simulation.Simulation.Observe], or passed to
+// [github.com/michael-ryan/cellularautomata/v2.Config] to record a simulation running in the GUI.
+package stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/michael-ryan/cellularautomata/v2/simulation"
+)
+
+// Record holds the statistics of a single generation.
+type Record struct {
+	// Run is the number of the run this generation belongs to, counting from 0. A new run starts each time the recorder is registered with a simulation.
+	Run uint `json:"run"`
+	// Generation is the generation these statistics describe.
+	Generation uint `json:"generation"`
+	// Counts[n] is the number of cells in state n.
+	Counts []uint `json:"counts"`
+	// Births[n] is the number of cells that entered state n in this generation.
+	Births []uint `json:"births"`
+	// Deaths[n] is the number of cells that left state n in this generation.
+	Deaths []uint `json:"deaths"`
+	// Transitions[from][to] is the number of cells that changed from state from to state to in this generation.
+	// Transitions[n][n] is the number of cells that stayed in state n.
+	Transitions [][]uint `json:"transitions"`
+}
+
+// Recorder is a [simulation.Observer] that keeps a [Record] for every generation it observes. You should use the [NewRecorder] function to create one.
+//
+// A Recorder can be registered with more than one simulation in turn, such as each time the GUI is reset. It tells them apart by [Record.Run].
+//
+// A Recorder is not safe for concurrent use, so records should only be read once the simulation is no longer running.
+type Recorder struct {
+	states  uint
+	records []Record
+	// runs is the number of runs observed so far, the last of which is still being observed
+	runs uint
+}
+
+var _ simulation.Rewinder = (*Recorder)(nil)
+
+// NewRecorder constructs a Recorder for an automaton with the given number of states, as reported by [github.com/michael-ryan/cellularautomata/v2/model.Automaton.CountStates].
+func NewRecorder(states uint) *Recorder {
+	return &Recorder{states: states}
+}
+
+// Observe records the statistics of a generation. It implements [simulation.Observer].
+//
+// If previous is nil, the recorder has just been registered, so a new run starts. No births, deaths or transitions are recorded, only the counts of current.
+// Cells with a state the recorder does not know about are ignored.
+func (r *Recorder) Observe(generation uint, previous, current [][]uint) {
+	if previous == nil || r.runs == 0 {
+		r.runs++
+	}
+
+	record := Record{
+		Run:         r.runs - 1,
+		Generation:  generation,
+		Counts:      make([]uint, r.states),
+		Births:      make([]uint, r.states),
+		Deaths:      make([]uint, r.states),
+		Transitions: make([][]uint, r.states),
+	}
+	for state := range record.Transitions {
+		record.Transitions[state] = make([]uint, r.states)
+	}
+
+	for x := range current {
+		for y, state := range current[x] {
+			if state >= r.states {
+				continue
+			}
+			record.Counts[state]++
+
+			if previous == nil {
+				continue
+			}
+
+			old := previous[x][y]
+			if old >= r.states {
+				continue
+			}
+			record.Transitions[old][state]++
+			if old != state {
+				record.Births[state]++
+				record.Deaths[old]++
+			}
+		}
+	}
+
+	r.records = append(r.records, record)
+}
+
+// Rewound forgets the records of every generation of the current run after generation, as the simulation has been rewound to it. It implements [simulation.Rewinder].
+func (r *Recorder) Rewound(generation uint) {
+	for len(r.records) > 0 && r.records[len(r.records)-1].Run == r.runs-1 && r.records[len(r.records)-1].Generation > generation {
+		r.records = r.records[:len(r.records)-1]
+	}
+}
+
+// Records returns every record observed so far, in the order they were observed.
+func (r *Recorder) Records() []Record {
+	records := make([]Record, len(r.records))
+	copy(records, r.records)
+	return records
+}
+
+// WriteCSV writes every record as a row of CSV, preceded by a header row.
+//
+// The columns are the run and the generation, then count_n, births_n and deaths_n for each state n, then transitions_a_b for each pair of distinct states a and b.
+func (r *Recorder) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+
+	header := []string{"run", "generation"}
+	for _, prefix := range []string{"count", "births", "deaths"} {
+		for state := range r.states {
+			header = append(header, fmt.Sprintf("%v_%v", prefix, state))
+		}
+	}
+	for from := range r.states {
+		for to := range r.states {
+			if from != to {
+				header = append(header, fmt.Sprintf("transitions_%v_%v", from, to))
+			}
+		}
+	}
+
+	if err := out.Write(header); err != nil {
+		return err
+	}
+
+	for _, record := range r.records {
+		row := []string{strconv.FormatUint(uint64(record.Run), 10), strconv.FormatUint(uint64(record.Generation), 10)}
+		for _, values := range [][]uint{record.Counts, record.Births, record.Deaths} {
+			for _, v := range values {
+				row = append(row, strconv.FormatUint(uint64(v), 10))
+			}
+		}
+		for from := range record.Transitions {
+			for to, v := range record.Transitions[from] {
+				if from != to {
+					row = append(row, strconv.FormatUint(uint64(v), 10))
+				}
+			}
+		}
+
+		if err := out.Write(row); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// WriteJSONLines writes every record as a JSON object, one per line.
+func (r *Recorder) WriteJSONLines(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	for _, record := range r.records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
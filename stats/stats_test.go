@@ -0,0 +1,162 @@
+package stats
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/michael-ryan/cellularautomata/v2/examples"
+	"github.com/michael-ryan/cellularautomata/v2/simulation"
+)
+
+func TestRecorder_Observe(t *testing.T) {
+	r := NewRecorder(3)
+	r.Observe(0, nil, [][]uint{{0, 1}, {2, 2}})
+	r.Observe(1, [][]uint{{0, 1}, {2, 2}}, [][]uint{{1, 1}, {0, 2}})
+
+	want := []Record{
+		{
+			Generation:  0,
+			Counts:      []uint{1, 1, 2},
+			Births:      []uint{0, 0, 0},
+			Deaths:      []uint{0, 0, 0},
+			Transitions: [][]uint{{0, 0, 0}, {0, 0, 0}, {0, 0, 0}},
+		},
+		{
+			Generation:  1,
+			Counts:      []uint{1, 2, 1},
+			Births:      []uint{1, 1, 0},
+			Deaths:      []uint{1, 0, 1},
+			Transitions: [][]uint{{0, 1, 0}, {0, 1, 0}, {1, 0, 1}},
+		},
+	}
+	if got := r.Records(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Recorder.Records() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRecorder_Simulation(t *testing.T) {
+	blinker := [][]uint{
+		{0, 0, 0},
+		{1, 1, 1},
+		{0, 0, 0},
+	}
+
+	s, err := simulation.New(examples.NewConways(), blinker)
+	if err != nil {
+		t.Fatalf("simulation.New() error = %v", err)
+	}
+
+	r := NewRecorder(2)
+	s.Observe(r)
+	s.Step()
+
+	records := r.Records()
+	if len(records) != 2 {
+		t.Fatalf("len(Recorder.Records()) = %v, want %v", len(records), 2)
+	}
+	if got, want := records[1].Births, []uint{2, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Recorder.Records()[1].Births = %v, want %v", got, want)
+	}
+	if got, want := records[1].Counts, []uint{6, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Recorder.Records()[1].Counts = %v, want %v", got, want)
+	}
+}
+
+func TestRecorder_Rewound(t *testing.T) {
+	s, err := simulation.New(examples.NewConways(), [][]uint{
+		{0, 0, 0},
+		{1, 1, 1},
+		{0, 0, 0},
+	})
+	if err != nil {
+		t.Fatalf("simulation.New() error = %v", err)
+	}
+	s.SetHistory(10)
+
+	r := NewRecorder(2)
+	s.Observe(r)
+	for range 5 {
+		s.Step()
+	}
+	if err := s.Rewind(2); err != nil {
+		t.Fatalf("Simulation.Rewind() error = %v", err)
+	}
+	s.Step()
+
+	var generations []uint
+	for _, record := range r.Records() {
+		generations = append(generations, record.Generation)
+	}
+	if want := []uint{0, 1, 2, 3}; !reflect.DeepEqual(generations, want) {
+		t.Errorf("generations recorded = %v, want %v", generations, want)
+	}
+}
+
+func TestRecorder_Runs(t *testing.T) {
+	blinker := [][]uint{
+		{0, 0, 0},
+		{1, 1, 1},
+		{0, 0, 0},
+	}
+
+	r := NewRecorder(2)
+	for _, steps := range []int{2, 1} {
+		s, err := simulation.New(examples.NewConways(), blinker)
+		if err != nil {
+			t.Fatalf("simulation.New() error = %v", err)
+		}
+		s.SetHistory(10)
+		s.Observe(r)
+		for range steps {
+			s.Step()
+		}
+		// rewinding the second run must not touch the records of the first
+		if err := s.Rewind(0); err != nil {
+			t.Fatalf("Simulation.Rewind() error = %v", err)
+		}
+	}
+
+	var got [][2]uint
+	for _, record := range r.Records() {
+		got = append(got, [2]uint{record.Run, record.Generation})
+	}
+	if want := [][2]uint{{0, 0}, {1, 0}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("runs and generations recorded = %v, want %v", got, want)
+	}
+}
+
+func TestRecorder_WriteCSV(t *testing.T) {
+	r := NewRecorder(2)
+	r.Observe(0, nil, [][]uint{{0, 1}})
+	r.Observe(1, [][]uint{{0, 1}}, [][]uint{{1, 1}})
+
+	w := &bytes.Buffer{}
+	if err := r.WriteCSV(w); err != nil {
+		t.Fatalf("Recorder.WriteCSV() error = %v", err)
+	}
+
+	want := "run,generation,count_0,count_1,births_0,births_1,deaths_0,deaths_1,transitions_0_1,transitions_1_0\n" +
+		"0,0,1,1,0,0,0,0,0,0\n" +
+		"0,1,0,2,0,1,1,0,1,0\n"
+	if got := w.String(); got != want {
+		t.Errorf("Recorder.WriteCSV() = %q, want %q", got, want)
+	}
+}
+
+func TestRecorder_WriteJSONLines(t *testing.T) {
+	r := NewRecorder(2)
+	r.Observe(0, nil, [][]uint{{0, 1}})
+	r.Observe(1, [][]uint{{0, 1}}, [][]uint{{1, 1}})
+
+	w := &bytes.Buffer{}
+	if err := r.WriteJSONLines(w); err != nil {
+		t.Fatalf("Recorder.WriteJSONLines() error = %v", err)
+	}
+
+	want := `{"run":0,"generation":0,"counts":[1,1],"births":[0,0],"deaths":[0,0],"transitions":[[0,0],[0,0]]}` + "\n" +
+		`{"run":0,"generation":1,"counts":[0,2],"births":[0,1],"deaths":[1,0],"transitions":[[0,1],[0,1]]}` + "\n"
+	if got := w.String(); got != want {
+		t.Errorf("Recorder.WriteJSONLines() = %q, want %q", got, want)
+	}
+}
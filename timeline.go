@@ -0,0 +1,56 @@
+package cellularautomata
+
+import (
+	"github.com/gopxl/pixel/v2"
+)
+
+// timelineHeight is the height of the timeline drawn across the bottom of the window, in real pixels.
+const timelineHeight = 8
+
+// timeline is a bar across the bottom of the window, spanning the generations that can be rewound to, with a marker at the generation being shown.
+type timeline struct {
+	// realWidth is the width of the window, in real pixels
+	realWidth uint
+}
+
+// x returns the real x coordinate of generation on a timeline from earliest to latest.
+func (t timeline) x(generation, earliest, latest uint) uint {
+	if latest == earliest {
+		return t.realWidth - 1
+	}
+	return (generation - earliest) * (t.realWidth - 1) / (latest - earliest)
+}
+
+// at returns the generation under the real pixel xy, on a timeline from earliest to latest, reporting false if xy is not on the timeline.
+func (t timeline) at(xy pixel.Vec, earliest, latest uint) (uint, bool) {
+	if xy.X < 0 || xy.Y < 0 || xy.Y >= timelineHeight || uint(xy.X) >= t.realWidth {
+		return 0, false
+	}
+
+	if t.realWidth == 1 {
+		return latest, true
+	}
+	// round to the nearest generation, so both ends can be reached
+	span := latest - earliest
+	return earliest + (uint(xy.X)*span+(t.realWidth-1)/2)/(t.realWidth-1), true
+}
+
+// draw paints the timeline from earliest to latest over pixels, a premultiplied frame as returned by [canvas.paint], with its marker at cursor.
+func (t timeline) draw(pixels []uint8, earliest, cursor, latest uint) {
+	marker := t.x(cursor, earliest, latest)
+	for x := range t.realWidth {
+		// the generations up to the one being shown are lighter than those after it
+		shade := uint8(64)
+		switch {
+		case x+1 >= marker && x <= marker+1:
+			shade = 255
+		case x < marker:
+			shade = 160
+		}
+
+		for y := range uint(timelineHeight) {
+			i := getRealPixelIndex(x, y, t.realWidth)
+			pixels[i], pixels[i+1], pixels[i+2], pixels[i+3] = shade, shade, shade, 255
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package cellularautomata
+
+import (
+	"math"
+
+	"github.com/gopxl/pixel/v2"
+	"github.com/gopxl/pixel/v2/backends/opengl"
+)
+
+const (
+	// maxZoom is the furthest the viewport can zoom in, as the number of real pixels each pixel of the whole canvas is drawn with.
+	maxZoom = 64
+	// zoomStep is how much each notch of the mouse wheel zooms in or out by.
+	zoomStep = 1.25
+)
+
+// viewport is the part of the canvas shown in the window. At a zoom of 1, the whole canvas is shown, exactly filling the window.
+type viewport struct {
+	// Zoom is the number of real pixels each pixel of the whole canvas is drawn with, from 1 up to maxZoom
+	Zoom float64
+	// X and Y are the point of the whole canvas drawn at the bottom left corner of the window, in real pixels
+	X, Y float64
+}
+
+// toCanvas returns the point of the whole canvas drawn at the real pixel xy of the window.
+func (v viewport) toCanvas(xy pixel.Vec) pixel.Vec {
+	return pixel.Vec{X: v.X + xy.X/v.Zoom, Y: v.Y + xy.Y/v.Zoom}
+}
+
+// zoomAt multiplies the zoom by factor, keeping the point of the canvas under the real pixel xy where it is.
+func (v *viewport) zoomAt(xy pixel.Vec, factor float64, realWidth, realHeight uint) {
+	fixed := v.toCanvas(xy)
+	v.Zoom = math.Min(math.Max(v.Zoom*factor, 1), maxZoom)
+	v.X, v.Y = fixed.X-xy.X/v.Zoom, fixed.Y-xy.Y/v.Zoom
+	v.clamp(realWidth, realHeight)
+}
+
+// pan moves the viewport so the canvas follows a drag of delta real pixels.
+func (v *viewport) pan(delta pixel.Vec, realWidth, realHeight uint) {
+	v.X -= delta.X / v.Zoom
+	v.Y -= delta.Y / v.Zoom
+	v.clamp(realWidth, realHeight)
+}
+
+// clamp keeps the viewport within the canvas, which is realWidth by realHeight real pixels when shown whole.
+func (v *viewport) clamp(realWidth, realHeight uint) {
+	v.X = math.Min(math.Max(v.X, 0), float64(realWidth)-float64(realWidth)/v.Zoom)
+	v.Y = math.Min(math.Max(v.Y, 0), float64(realHeight)-float64(realHeight)/v.Zoom)
+}
+
+// handleView zooms the viewport with the mouse wheel, pans it by dragging with the middle mouse button, and shows the whole canvas again on Home.
+func (c *canvas) handleView(win *opengl.Window) {
+	if scroll := win.MouseScroll().Y; scroll != 0 {
+		c.View.zoomAt(win.MousePosition(), math.Pow(zoomStep, scroll), c.RealWidth, c.RealHeight)
+	}
+
+	if win.Pressed(pixel.MouseButtonMiddle) {
+		c.View.pan(win.MousePosition().Sub(win.MousePreviousPosition()), c.RealWidth, c.RealHeight)
+	}
+
+	if win.JustPressed(pixel.KeyHome) {
+		c.View = viewport{Zoom: 1}
+	}
+}